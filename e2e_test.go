@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -238,16 +241,18 @@ func TestMergePR(t *testing.T) {
 func TestReassignReviewer(t *testing.T) {
 	teamName := generateID("team")
 	author := generateID("user")
-	oldReviewer := generateID("user")
-	newReviewer := generateID("user")
+	reviewer1 := generateID("user")
+	reviewer2 := generateID("user")
+	spare := generateID("user")
 
-	// Создаем команду
+	// Создаем команду: spare активируется после создания PR и станет единственным кандидатом на замену
 	team := map[string]interface{}{
 		"team_name": teamName,
 		"members": []map[string]interface{}{
 			{"user_id": author, "username": "Author", "is_active": true},
-			{"user_id": oldReviewer, "username": "OldReviewer", "is_active": true},
-			{"user_id": newReviewer, "username": "NewReviewer", "is_active": true},
+			{"user_id": reviewer1, "username": "Reviewer1", "is_active": true},
+			{"user_id": reviewer2, "username": "Reviewer2", "is_active": true},
+			{"user_id": spare, "username": "Spare", "is_active": false},
 		},
 	}
 
@@ -278,17 +283,24 @@ func TestReassignReviewer(t *testing.T) {
 	pr := createResult["pull_request"].(map[string]interface{})
 	reviewers := pr["assigned_reviewers"].([]interface{})
 
-	if len(reviewers) == 0 {
-		t.Fatal("Нет ревьюверов для переназначения")
+	if len(reviewers) != 2 {
+		t.Fatalf("Ожидалось 2 ревьювера, назначено %d", len(reviewers))
 	}
 
 	oldReviewerID := reviewers[0].(string)
 
-	// Переназначаем ревьювера
+	_, err = makeRequest("POST", baseURL+"/users/setIsActive", map[string]interface{}{
+		"user_id":   spare,
+		"is_active": true,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка активации пользователя: %v", err)
+	}
+
+	// Переназначаем ревьювера, замену выбирает сервис
 	reassignReq := map[string]interface{}{
 		"pull_request_id": prID,
-		"old_reviewer_id": oldReviewerID,
-		"new_reviewer_id": newReviewer,
+		"old_user_id":     oldReviewerID,
 	}
 
 	resp2, err := makeRequest("POST", baseURL+"/pullRequest/reassign", reassignReq)
@@ -307,14 +319,21 @@ func TestReassignReviewer(t *testing.T) {
 		t.Fatalf("Ошибка декодирования ответа: %v", err)
 	}
 
-	updatedPR := result["pull_request"].(map[string]interface{})
+	// Единственный подходящий кандидат — участник, который ещё не был назначен
+	if result["replaced_by"] != spare {
+		t.Fatalf("Ожидалась замена на %s, получено %v", spare, result["replaced_by"])
+	}
+
+	updatedPR := result["pr"].(map[string]interface{})
 	updatedReviewers := updatedPR["assigned_reviewers"].([]interface{})
 
 	found := false
 	for _, r := range updatedReviewers {
-		if r.(string) == newReviewer {
+		if r.(string) == oldReviewerID {
+			t.Fatal("Старый ревьювер остался в списке")
+		}
+		if r.(string) == spare {
 			found = true
-			break
 		}
 	}
 
@@ -323,6 +342,116 @@ func TestReassignReviewer(t *testing.T) {
 	}
 }
 
+func TestReassignWithoutCandidate(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer1 := generateID("user")
+	reviewer2 := generateID("user")
+
+	// Все участники, кроме автора, уже будут назначены
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer1, "username": "Reviewer1", "is_active": true},
+			{"user_id": reviewer2, "username": "Reviewer2", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	prReq := map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Test PR",
+		"author_id":         author,
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", prReq)
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	reassignReq := map[string]interface{}{
+		"pull_request_id": prID,
+		"old_user_id":     reviewer1,
+	}
+
+	resp, err := makeRequest("POST", baseURL+"/pullRequest/reassign", reassignReq)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusConflict {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 409, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var errorResult map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&errorResult); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	errorObj := errorResult["error"].(map[string]interface{})
+	if errorObj["code"] != "NO_CANDIDATE" {
+		t.Fatalf("Ожидалась ошибка NO_CANDIDATE, получена: %v", errorObj["code"])
+	}
+}
+
+func TestCreateDuplicatePR(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	statuses := make(chan int, 5)
+	for i := 0; i < cap(statuses); i++ {
+		go func(i int) {
+			prReq := map[string]interface{}{
+				"pull_request_id":   prID,
+				"pull_request_name": fmt.Sprintf("Attempt %d", i),
+				"author_id":         author,
+			}
+			resp, err := makeRequest("POST", baseURL+"/pullRequest/create", prReq)
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close() //nolint:errcheck
+			statuses <- resp.StatusCode
+		}(i)
+	}
+
+	created := 0
+	for i := 0; i < cap(statuses); i++ {
+		switch status := <-statuses; status {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+		default:
+			t.Fatalf("Ожидался статус 201 или 409, получен %d", status)
+		}
+	}
+
+	if created != 1 {
+		t.Fatalf("Ожидалось ровно одно создание PR, получено %d", created)
+	}
+}
+
 func TestCannotReassignAfterMerge(t *testing.T) {
 	teamName := generateID("team")
 	author := generateID("user")
@@ -385,8 +514,7 @@ func TestCannotReassignAfterMerge(t *testing.T) {
 	// Пытаемся переназначить после merge
 	reassignReq := map[string]interface{}{
 		"pull_request_id": prID,
-		"old_reviewer_id": oldReviewerID,
-		"new_reviewer_id": newReviewer,
+		"old_user_id":     oldReviewerID,
 	}
 
 	resp2, err := makeRequest("POST", baseURL+"/pullRequest/reassign", reassignReq)
@@ -546,6 +674,101 @@ func TestGetUserPullRequests(t *testing.T) {
 	}
 }
 
+func TestGetUserPullRequestsFilters(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	var prIDs []string
+	for i := 0; i < 3; i++ {
+		prID := generateID("pr")
+		_, err = makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+			"pull_request_id":   prID,
+			"pull_request_name": "Test PR",
+			"author_id":         author,
+		})
+		if err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+		prIDs = append(prIDs, prID)
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": prIDs[1],
+	})
+	if err != nil {
+		t.Fatalf("Ошибка merge PR: %v", err)
+	}
+
+	// Открытые PR от новых к старым, по одному на страницу
+	var got []string
+	url := baseURL + "/users/getReview?user_id=" + reviewer + "&status=OPEN&sort=created_desc&limit=1"
+	for page := 0; page < 3; page++ {
+		resp, err := makeRequest("GET", url, nil)
+		if err != nil {
+			t.Fatalf("Ошибка запроса: %v", err)
+		}
+
+		var result struct {
+			PullRequests []struct {
+				PullRequestId string  `json:"pull_request_id"`
+				CreatedAt     *string `json:"createdAt"`
+			} `json:"pull_requests"`
+			NextCursor string `json:"next_cursor"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close() //nolint:errcheck
+		if err != nil {
+			t.Fatalf("Ошибка декодирования ответа: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || len(result.PullRequests) != 1 {
+			t.Fatalf("Ожидался статус 200 и один PR, получен %d: %+v", resp.StatusCode, result)
+		}
+		if result.PullRequests[0].CreatedAt == nil {
+			t.Fatal("PR должен содержать createdAt")
+		}
+
+		got = append(got, result.PullRequests[0].PullRequestId)
+		if result.NextCursor == "" {
+			break
+		}
+		url = baseURL + "/users/getReview?user_id=" + reviewer + "&status=OPEN&sort=created_desc&limit=1&cursor=" + result.NextCursor
+	}
+
+	if len(got) != 2 || got[0] != prIDs[2] || got[1] != prIDs[0] {
+		t.Fatalf("Ожидались PR %v, получены %v", []string{prIDs[2], prIDs[0]}, got)
+	}
+
+	for _, query := range []string{"&sort=newest", "&limit=0", "&limit=101", "&limit=abc", "&created_after=yesterday", "&cursor=garbage"} {
+		resp, err := makeRequest("GET", baseURL+"/users/getReview?user_id="+reviewer+query, nil)
+		if err != nil {
+			t.Fatalf("Ошибка запроса: %v", err)
+		}
+		var errResp map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&errResp)
+		resp.Body.Close() //nolint:errcheck
+		if err != nil {
+			t.Fatalf("Ошибка декодирования ответа: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest || errResp["error"].(map[string]interface{})["code"] != "INVALID_REQUEST" {
+			t.Fatalf("%s: ожидался статус 400 INVALID_REQUEST, получен %d: %v", query, resp.StatusCode, errResp)
+		}
+	}
+}
+
 func TestSetUserActive(t *testing.T) {
 	teamName := generateID("team")
 	userID := generateID("user")
@@ -591,6 +814,978 @@ func TestSetUserActive(t *testing.T) {
 	}
 }
 
+func TestTeamSettings(t *testing.T) {
+	teamName := generateID("team")
+	user1 := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": user1, "username": "Alice", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	// По умолчанию используется стратегия least_loaded
+	resp, err := makeRequest("GET", baseURL+"/team/getSettings?team_name="+teamName, nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var settings map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if settings["reviewer_strategy"] != "least_loaded" {
+		t.Fatalf("Ожидалась стратегия least_loaded, получена %v", settings["reviewer_strategy"])
+	}
+
+	// Меняем стратегию
+	setReq := map[string]interface{}{
+		"team_name":         teamName,
+		"reviewer_strategy": "round_robin",
+	}
+
+	resp2, err := makeRequest("POST", baseURL+"/team/setSettings", setReq)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp2.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp2.StatusCode, string(body))
+	}
+
+	// Неизвестная стратегия отклоняется
+	setReq["reviewer_strategy"] = "alphabetical"
+	resp3, err := makeRequest("POST", baseURL+"/team/setSettings", setReq)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp3.Body.Close() //nolint:errcheck
+
+	if resp3.StatusCode != http.StatusBadRequest {
+		body, _ := io.ReadAll(resp3.Body)
+		t.Fatalf("Ожидался статус 400, получен %d. Тело: %s", resp3.StatusCode, string(body))
+	}
+
+	var errorResult map[string]interface{}
+	if err := json.NewDecoder(resp3.Body).Decode(&errorResult); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	errorObj := errorResult["error"].(map[string]interface{})
+	if errorObj["code"] != "INVALID_SETTINGS" {
+		t.Fatalf("Ожидалась ошибка INVALID_SETTINGS, получена: %v", errorObj["code"])
+	}
+}
+
+func TestTeamReviewersRequired(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	user1 := generateID("user")
+	user2 := generateID("user")
+	user3 := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name":          teamName,
+		"reviewers_required": 1,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": user1, "username": "Alice", "is_active": true},
+			{"user_id": user2, "username": "Bob", "is_active": true},
+			{"user_id": user3, "username": "Carol", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	resp, err := makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "One reviewer",
+		"author_id":         author,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	reviewers := created["pull_request"].(map[string]interface{})["assigned_reviewers"].([]interface{})
+	if len(reviewers) != 1 {
+		t.Fatalf("Ожидался 1 ревьювер, назначено %v", reviewers)
+	}
+
+	// Повышаем требование до 3
+	resp2, err := makeRequest("POST", baseURL+"/team/setReviewersRequired", map[string]interface{}{
+		"team_name":          teamName,
+		"reviewers_required": 3,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp2.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp2.StatusCode, string(body))
+	}
+
+	resp3, err := makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   generateID("pr"),
+		"pull_request_name": "Three reviewers",
+		"author_id":         author,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp3.Body.Close() //nolint:errcheck
+
+	if err := json.NewDecoder(resp3.Body).Decode(&created); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	reviewers = created["pull_request"].(map[string]interface{})["assigned_reviewers"].([]interface{})
+	if len(reviewers) != 3 {
+		t.Fatalf("Ожидалось 3 ревьювера, назначено %v", reviewers)
+	}
+
+	// Недопустимое значение отклоняется
+	resp4, err := makeRequest("POST", baseURL+"/team/setReviewersRequired", map[string]interface{}{
+		"team_name":          teamName,
+		"reviewers_required": 0,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp4.Body.Close() //nolint:errcheck
+
+	if resp4.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Ожидался статус 400, получен %d", resp4.StatusCode)
+	}
+}
+
+func TestDeactivateTeamUsers(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer1 := generateID("user")
+	reviewer2 := generateID("user")
+	spare := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer1, "username": "Reviewer1", "is_active": true},
+			{"user_id": reviewer2, "username": "Reviewer2", "is_active": true},
+			{"user_id": spare, "username": "Spare", "is_active": false},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	prReq := map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Test PR",
+		"author_id":         author,
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", prReq)
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	// spare активируется после создания PR: он станет заменой одного
+	// из деактивируемых, а второго заменить будет некем
+	_, err = makeRequest("POST", baseURL+"/users/setIsActive", map[string]interface{}{
+		"user_id":   spare,
+		"is_active": true,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка активации пользователя: %v", err)
+	}
+
+	deactivateReq := map[string]interface{}{
+		"team_name": teamName,
+		"user_ids":  []string{reviewer1, reviewer2},
+	}
+
+	resp, err := makeRequest("POST", baseURL+"/team/deactivateUsers", deactivateReq)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	report := result["report"].(map[string]interface{})
+	if len(report["deactivated_users"].([]interface{})) != 2 {
+		t.Fatalf("Ожидалась деактивация 2 пользователей, получено %v", report["deactivated_users"])
+	}
+
+	reassigned := report["reassigned"].([]interface{})
+	if len(reassigned) != 1 {
+		t.Fatalf("Ожидалось 1 переназначение, получено %v", reassigned)
+	}
+	if reassigned[0].(map[string]interface{})["new_user_id"] != spare {
+		t.Fatalf("Ожидалась замена на %s, получено %v", spare, reassigned[0])
+	}
+
+	if len(report["not_reassigned"].([]interface{})) != 1 {
+		t.Fatalf("Ожидался 1 непереназначенный PR, получено %v", report["not_reassigned"])
+	}
+
+	// Деактивированные пользователи больше не активны
+	resp2, err := makeRequest("GET", baseURL+"/team/get?team_name="+teamName, nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	var teamResult map[string]interface{}
+	if err := json.NewDecoder(resp2.Body).Decode(&teamResult); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	for _, m := range teamResult["members"].([]interface{}) {
+		member := m.(map[string]interface{})
+		if (member["user_id"] == reviewer1 || member["user_id"] == reviewer2) && member["is_active"] != false {
+			t.Fatalf("Пользователь %v должен быть неактивен", member["user_id"])
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer1 := generateID("user")
+	reviewer2 := generateID("user")
+	spare := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer1, "username": "Reviewer1", "is_active": true},
+			{"user_id": reviewer2, "username": "Reviewer2", "is_active": true},
+			{"user_id": spare, "username": "Spare", "is_active": false},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	prReq := map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Test PR",
+		"author_id":         author,
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", prReq)
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	_, err = makeRequest("POST", baseURL+"/users/setIsActive", map[string]interface{}{
+		"user_id":   spare,
+		"is_active": true,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка активации пользователя: %v", err)
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/reassign", map[string]interface{}{
+		"pull_request_id": prID,
+		"old_user_id":     reviewer1,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка переназначения: %v", err)
+	}
+
+	// reviewer1 был назначен и заменён, поэтому у него одно назначение и одна замена
+	resp, err := makeRequest("GET", baseURL+"/stats/reviewers?team_name="+teamName, nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	reviewers := result["reviewers"].([]interface{})
+	if len(reviewers) != 4 {
+		t.Fatalf("Ожидалась статистика по 4 пользователям, получено %d", len(reviewers))
+	}
+	for _, r := range reviewers {
+		stats := r.(map[string]interface{})
+		if stats["user_id"] != reviewer1 {
+			continue
+		}
+		if stats["assignments"] != float64(1) || stats["open_assignments"] != float64(0) || stats["reassigned_away"] != float64(1) {
+			t.Fatalf("Неверная статистика заменённого ревьювера: %v", stats)
+		}
+	}
+
+	resp2, err := makeRequest("GET", baseURL+"/stats/teams", nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	var teamsResult map[string]interface{}
+	if err := json.NewDecoder(resp2.Body).Decode(&teamsResult); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	found := false
+	for _, tm := range teamsResult["teams"].([]interface{}) {
+		stats := tm.(map[string]interface{})
+		if stats["team_name"] != teamName {
+			continue
+		}
+		found = true
+		if stats["members"] != float64(4) || stats["assignments"] != float64(3) ||
+			stats["open_assignments"] != float64(2) || stats["reassigned_away"] != float64(1) {
+			t.Fatalf("Неверная статистика команды: %v", stats)
+		}
+	}
+	if !found {
+		t.Fatal("Команда не найдена в статистике")
+	}
+}
+
+func TestHealth(t *testing.T) {
+	resp, err := makeRequest("GET", baseURL+"/health/live", nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Ожидался статус 200 для live, получен %d", resp.StatusCode)
+	}
+
+	resp, err = makeRequest("GET", baseURL+"/health/ready", nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 200 для ready, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	if result["status"] != "ok" || result["migration_version"] == nil {
+		t.Fatalf("Неверный ответ готовности: %v", result)
+	}
+}
+
+func TestPullRequestHistory(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	user1 := generateID("user")
+	user2 := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": user1, "username": "Alice", "is_active": true},
+			{"user_id": user2, "username": "Bob", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "History",
+		"author_id":         author,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка merge PR: %v", err)
+	}
+
+	resp, err := makeRequest("GET", baseURL+"/pullRequest/history?pull_request_id="+prID, nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+
+	events := result["events"].([]interface{})
+	var types []string
+	for _, e := range events {
+		types = append(types, e.(map[string]interface{})["event_type"].(string))
+	}
+	want := []string{"created", "reviewer_assigned", "reviewer_assigned", "merged"}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("Ожидались события %v, получено %v", want, types)
+	}
+
+	resp2, err := makeRequest("GET", baseURL+"/pullRequest/history?pull_request_id="+generateID("pr"), nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusNotFound {
+		t.Fatalf("Ожидался статус 404, получен %d", resp2.StatusCode)
+	}
+}
+
+func TestCloseAndReopenPR(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer := generateID("user")
+	spare := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+			{"user_id": spare, "username": "Spare", "is_active": false},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Abandoned",
+		"author_id":         author,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	resp, err := makeRequest("POST", baseURL+"/pullRequest/close", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var closed map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&closed); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	pr := closed["pull_request"].(map[string]interface{})
+	if pr["status"] != "CLOSED" || pr["closedAt"] == nil {
+		t.Fatalf("PR не закрыт: %v", pr)
+	}
+
+	resp2, err := makeRequest("POST", baseURL+"/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusConflict {
+		t.Fatalf("Merge закрытого PR: ожидался статус 409, получен %d", resp2.StatusCode)
+	}
+
+	// Пока PR закрыт, ревьювер уходит, а запасной участник становится активным
+	for userID, active := range map[string]bool{reviewer: false, spare: true} {
+		_, err = makeRequest("POST", baseURL+"/users/setIsActive", map[string]interface{}{
+			"user_id":   userID,
+			"is_active": active,
+		})
+		if err != nil {
+			t.Fatalf("Ошибка изменения активности: %v", err)
+		}
+	}
+
+	resp3, err := makeRequest("POST", baseURL+"/pullRequest/reopen", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp3.Body.Close() //nolint:errcheck
+
+	if resp3.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp3.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp3.StatusCode, string(body))
+	}
+
+	var reopened map[string]interface{}
+	if err := json.NewDecoder(resp3.Body).Decode(&reopened); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	pr = reopened["pull_request"].(map[string]interface{})
+	reviewers := pr["assigned_reviewers"].([]interface{})
+	if pr["status"] != "OPEN" || pr["closedAt"] != nil {
+		t.Fatalf("PR не открыт: %v", pr)
+	}
+	if len(reviewers) != 1 || reviewers[0] != spare {
+		t.Fatalf("Ожидалась замена неактивного ревьювера на %s, получено %v", spare, reviewers)
+	}
+}
+
+func TestDraftPR(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prID := generateID("pr")
+	resp, err := makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Work in progress",
+		"author_id":         author,
+		"draft":             true,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 201, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	pr := created["pull_request"].(map[string]interface{})
+	if pr["draft"] != true || len(pr["assigned_reviewers"].([]interface{})) != 0 {
+		t.Fatalf("Черновик создан с ревьюверами: %v", pr)
+	}
+
+	resp2, err := makeRequest("POST", baseURL+"/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusConflict {
+		t.Fatalf("Merge черновика: ожидался статус 409, получен %d", resp2.StatusCode)
+	}
+
+	resp3, err := makeRequest("POST", baseURL+"/pullRequest/markReady", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp3.Body.Close() //nolint:errcheck
+
+	if resp3.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp3.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp3.StatusCode, string(body))
+	}
+
+	var ready map[string]interface{}
+	if err := json.NewDecoder(resp3.Body).Decode(&ready); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	pr = ready["pull_request"].(map[string]interface{})
+	reviewers := pr["assigned_reviewers"].([]interface{})
+	if pr["draft"] != false || len(reviewers) != 1 || reviewers[0] != reviewer {
+		t.Fatalf("Ожидался готовый PR с ревьювером %s, получено %v", reviewer, pr)
+	}
+}
+
+func TestReviewAndApprovals(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	_, err = makeRequest("POST", baseURL+"/team/setSettings", map[string]interface{}{
+		"team_name":          teamName,
+		"reviewer_strategy":  "first_n",
+		"approvals_required": 1,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+
+	prID := generateID("pr")
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   prID,
+		"pull_request_name": "Needs approval",
+		"author_id":         author,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	resp, err := makeRequest("POST", baseURL+"/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var errResp map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if resp.StatusCode != http.StatusConflict || errResp["error"].(map[string]interface{})["code"] != "NOT_ENOUGH_APPROVALS" {
+		t.Fatalf("Ожидался статус 409 NOT_ENOUGH_APPROVALS, получен %d: %v", resp.StatusCode, errResp)
+	}
+
+	resp2, err := makeRequest("POST", baseURL+"/pullRequest/review", map[string]interface{}{
+		"pull_request_id": prID,
+		"reviewer_id":     reviewer,
+		"verdict":         "APPROVED",
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp2.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp2.StatusCode, string(body))
+	}
+
+	var reviewed map[string]interface{}
+	if err := json.NewDecoder(resp2.Body).Decode(&reviewed); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	reviews := reviewed["pull_request"].(map[string]interface{})["reviews"].([]interface{})
+	if len(reviews) != 1 || reviews[0].(map[string]interface{})["verdict"] != "APPROVED" {
+		t.Fatalf("Ожидался вердикт APPROVED, получено %v", reviews)
+	}
+
+	resp3, err := makeRequest("POST", baseURL+"/pullRequest/merge", map[string]interface{}{
+		"pull_request_id": prID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp3.Body.Close() //nolint:errcheck
+
+	if resp3.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp3.Body)
+		t.Fatalf("Ожидался статус 200, получен %d. Тело: %s", resp3.StatusCode, string(body))
+	}
+}
+
+func TestGetAndListPR(t *testing.T) {
+	teamName := generateID("team")
+	author := generateID("user")
+	reviewer := generateID("user")
+
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+		},
+	}
+
+	_, err := makeRequest("POST", baseURL+"/team/add", team)
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	prIDs := []string{generateID("pr"), generateID("pr")}
+	for i, name := range []string{"Add search", "Fix login"} {
+		_, err = makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+			"pull_request_id":   prIDs[i],
+			"pull_request_name": name,
+			"author_id":         author,
+		})
+		if err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+	}
+
+	resp, err := makeRequest("GET", baseURL+"/pullRequest/get?pull_request_id="+prIDs[0], nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var got struct {
+		PullRequest struct {
+			PullRequestId     string   `json:"pull_request_id"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pull_request"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || got.PullRequest.PullRequestId != prIDs[0] || len(got.PullRequest.AssignedReviewers) != 1 {
+		t.Fatalf("Ожидался PR %s, получен %d: %+v", prIDs[0], resp.StatusCode, got)
+	}
+
+	resp2, err := makeRequest("GET", baseURL+"/pullRequest/get?pull_request_id="+generateID("missing"), nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	resp2.Body.Close() //nolint:errcheck
+	if resp2.StatusCode != http.StatusNotFound {
+		t.Fatalf("Ожидался статус 404, получен %d", resp2.StatusCode)
+	}
+
+	// list декодирует страницу списка PR
+	list := func(query string) ([]string, string) {
+		t.Helper()
+		resp, err := makeRequest("GET", baseURL+"/pullRequest/list?"+query, nil)
+		if err != nil {
+			t.Fatalf("Ошибка запроса: %v", err)
+		}
+		defer resp.Body.Close() //nolint:errcheck
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("%s: ожидался статус 200, получен %d. Тело: %s", query, resp.StatusCode, string(body))
+		}
+		var result struct {
+			PullRequests []struct {
+				PullRequestId string `json:"pull_request_id"`
+			} `json:"pull_requests"`
+			NextCursor string `json:"next_cursor"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Ошибка декодирования ответа: %v", err)
+		}
+		var ids []string
+		for _, pr := range result.PullRequests {
+			ids = append(ids, pr.PullRequestId)
+		}
+		return ids, result.NextCursor
+	}
+
+	first, cursor := list("team_name=" + teamName + "&limit=1")
+	if len(first) != 1 || first[0] != prIDs[0] || cursor == "" {
+		t.Fatalf("Ожидалась первая страница с %s и курсором, получено %v %q", prIDs[0], first, cursor)
+	}
+	second, cursor := list("team_name=" + teamName + "&limit=1&cursor=" + cursor)
+	if len(second) != 1 || second[0] != prIDs[1] || cursor != "" {
+		t.Fatalf("Ожидалась последняя страница с %s, получено %v %q", prIDs[1], second, cursor)
+	}
+
+	found, _ := list("author_id=" + author + "&reviewer_id=" + reviewer + "&status=OPEN&name=LOGIN")
+	if len(found) != 1 || found[0] != prIDs[1] {
+		t.Fatalf("Ожидался PR %s, получено %v", prIDs[1], found)
+	}
+
+	// Окно со смещением часового пояса сравнивается как момент времени, а не по часам на циферблате
+	msk := time.FixedZone("MSK", 3*60*60)
+	after := url.QueryEscape(time.Now().Add(-time.Minute).In(msk).Format(time.RFC3339))
+	inWindow, _ := list("team_name=" + teamName + "&created_after=" + after)
+	if len(inWindow) != 2 {
+		t.Fatalf("Ожидались оба PR в окне после %s, получено %v", after, inWindow)
+	}
+
+	for _, query := range []string{"limit=abc", "created_after=yesterday", "created_before=2026-13-01", "status=DRAFT"} {
+		resp, err := makeRequest("GET", baseURL+"/pullRequest/list?"+query, nil)
+		if err != nil {
+			t.Fatalf("Ошибка запроса: %v", err)
+		}
+		var errResp map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&errResp)
+		resp.Body.Close() //nolint:errcheck
+		if err != nil {
+			t.Fatalf("%s: ошибка декодирования ответа: %v", query, err)
+		}
+		if resp.StatusCode != http.StatusBadRequest || errResp["error"].(map[string]interface{})["code"] != "INVALID_REQUEST" {
+			t.Fatalf("%s: ожидался статус 400 INVALID_REQUEST, получен %d: %v", query, resp.StatusCode, errResp)
+		}
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer receiver.Close()
+
+	resp, err := makeRequest("POST", baseURL+"/webhooks/add", map[string]interface{}{
+		"url":    receiver.URL,
+		"secret": "s3cr3t",
+		"events": []string{"pull_request.created"},
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Ожидался статус 201, получен %d. Тело: %s", resp.StatusCode, string(body))
+	}
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	subscriptionID := created["subscription"].(map[string]interface{})["subscription_id"].(string)
+
+	teamName := generateID("team")
+	author := generateID("user")
+	_, err = makeRequest("POST", baseURL+"/team/add", map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": generateID("user"), "username": "Alice", "is_active": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	_, err = makeRequest("POST", baseURL+"/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   generateID("pr"),
+		"pull_request_name": "Webhook",
+		"author_id":         author,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	select {
+	case r := <-received:
+		if r.Header.Get("X-Webhook-Event") != "pull_request.created" || r.Header.Get("X-Webhook-Signature-256") == "" {
+			t.Fatalf("Неожиданные заголовки вебхука: %v", r.Header)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Вебхук не доставлен")
+	}
+
+	resp2, err := makeRequest("POST", baseURL+"/webhooks/delete", map[string]interface{}{
+		"subscription_id": subscriptionID,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp2.Body.Close() //nolint:errcheck
+
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", resp2.StatusCode)
+	}
+
+	resp3, err := makeRequest("POST", baseURL+"/webhooks/add", map[string]interface{}{
+		"url":    "ftp://example.com/hook",
+		"secret": "s3cret",
+		"events": []string{"pull_request.created"},
+	})
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp3.Body.Close() //nolint:errcheck
+
+	var errResp map[string]interface{}
+	if err := json.NewDecoder(resp3.Body).Decode(&errResp); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if resp3.StatusCode != http.StatusBadRequest || errResp["error"].(map[string]interface{})["code"] != "INVALID_REQUEST" {
+		t.Fatalf("Ожидался статус 400 INVALID_REQUEST, получен %d: %v", resp3.StatusCode, errResp)
+	}
+}
+
 // Генерируем уникальный ID для тестов
 func generateID(prefix string) string {
 	return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
@@ -15,26 +15,110 @@ import (
 
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	CONFLICT           ErrorResponseErrorCode = "CONFLICT"
+	INTERNAL           ErrorResponseErrorCode = "INTERNAL"
+	INVALIDREQUEST     ErrorResponseErrorCode = "INVALID_REQUEST"
+	INVALIDSETTINGS    ErrorResponseErrorCode = "INVALID_SETTINGS"
+	INVALIDSIGNATURE   ErrorResponseErrorCode = "INVALID_SIGNATURE"
+	NOCANDIDATE        ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED        ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTENOUGHAPPROVALS ErrorResponseErrorCode = "NOT_ENOUGH_APPROVALS"
+	NOTFOUND           ErrorResponseErrorCode = "NOT_FOUND"
+	PRCLOSED           ErrorResponseErrorCode = "PR_CLOSED"
+	PRDRAFT            ErrorResponseErrorCode = "PR_DRAFT"
+	PREXISTS           ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED           ErrorResponseErrorCode = "PR_MERGED"
+	TEAMEXISTS         ErrorResponseErrorCode = "TEAM_EXISTS"
+	UNAVAILABLE        ErrorResponseErrorCode = "UNAVAILABLE"
+)
+
+// Defines values for HealthStatusStatus.
+const (
+	Ok          HealthStatusStatus = "ok"
+	Unavailable HealthStatusStatus = "unavailable"
 )
 
 // Defines values for PullRequestStatus.
 const (
+	PullRequestStatusCLOSED PullRequestStatus = "CLOSED"
 	PullRequestStatusMERGED PullRequestStatus = "MERGED"
 	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
 )
 
+// Defines values for PullRequestEventType.
+const (
+	Closed           PullRequestEventType = "closed"
+	Created          PullRequestEventType = "created"
+	Merged           PullRequestEventType = "merged"
+	ReadyForReview   PullRequestEventType = "ready_for_review"
+	Reopened         PullRequestEventType = "reopened"
+	Reviewed         PullRequestEventType = "reviewed"
+	ReviewerAssigned PullRequestEventType = "reviewer_assigned"
+	ReviewerReplaced PullRequestEventType = "reviewer_replaced"
+)
+
 // Defines values for PullRequestShortStatus.
 const (
+	PullRequestShortStatusCLOSED PullRequestShortStatus = "CLOSED"
 	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
+// Defines values for ReviewVerdict.
+const (
+	APPROVED         ReviewVerdict = "APPROVED"
+	CHANGESREQUESTED ReviewVerdict = "CHANGES_REQUESTED"
+	COMMENTED        ReviewVerdict = "COMMENTED"
+)
+
+// Defines values for ReviewerStrategy.
+const (
+	FirstN      ReviewerStrategy = "first_n"
+	LeastLoaded ReviewerStrategy = "least_loaded"
+	Random      ReviewerStrategy = "random"
+	RoundRobin  ReviewerStrategy = "round_robin"
+)
+
+// Defines values for WebhookEventType.
+const (
+	PullRequestClosed         WebhookEventType = "pull_request.closed"
+	PullRequestCreated        WebhookEventType = "pull_request.created"
+	PullRequestMerged         WebhookEventType = "pull_request.merged"
+	PullRequestReadyForReview WebhookEventType = "pull_request.ready_for_review"
+	PullRequestReassigned     WebhookEventType = "pull_request.reassigned"
+	PullRequestReopened       WebhookEventType = "pull_request.reopened"
+	PullRequestReviewed       WebhookEventType = "pull_request.reviewed"
+	UserDeactivated           WebhookEventType = "user.deactivated"
+)
+
+// Defines values for SortQuery.
+const (
+	CreatedAsc  SortQuery = "created_asc"
+	CreatedDesc SortQuery = "created_desc"
+)
+
+// Defines values for GetPullRequestListParamsStatus.
+const (
+	GetPullRequestListParamsStatusCLOSED GetPullRequestListParamsStatus = "CLOSED"
+	GetPullRequestListParamsStatusMERGED GetPullRequestListParamsStatus = "MERGED"
+	GetPullRequestListParamsStatusOPEN   GetPullRequestListParamsStatus = "OPEN"
+)
+
+// Defines values for GetUsersGetReviewParamsStatus.
+const (
+	GetUsersGetReviewParamsStatusCLOSED GetUsersGetReviewParamsStatus = "CLOSED"
+	GetUsersGetReviewParamsStatusMERGED GetUsersGetReviewParamsStatus = "MERGED"
+	GetUsersGetReviewParamsStatusOPEN   GetUsersGetReviewParamsStatus = "OPEN"
+)
+
+// DeactivationReport defines model for DeactivationReport.
+type DeactivationReport struct {
+	DeactivatedUsers []string             `json:"deactivated_users"`
+	NotReassigned    []FailedReassignment `json:"not_reassigned"`
+	Reassigned       []Reassignment       `json:"reassigned"`
+	TeamName         string               `json:"team_name"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
@@ -46,24 +130,108 @@ type ErrorResponse struct {
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// FailedReassignment defines model for FailedReassignment.
+type FailedReassignment struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// Reason Код ошибки, из-за которой замена не выполнена (например, NO_CANDIDATE)
+	Reason string `json:"reason"`
+
+	// UserId user_id ревьювера, оставшегося назначенным
+	UserId string `json:"user_id"`
+}
+
+// HealthStatus defines model for HealthStatus.
+type HealthStatus struct {
+	// MigrationVersion Версия последней применённой миграции БД (только для /health/ready)
+	MigrationVersion *int64             `json:"migration_version,omitempty"`
+	Status           HealthStatusStatus `json:"status"`
+}
+
+// HealthStatusStatus defines model for HealthStatus.Status.
+type HealthStatusStatus string
+
+// IntegrationResult defines model for IntegrationResult.
+type IntegrationResult struct {
+	// Action Действие из входящего события
+	Action      *string      `json:"action,omitempty"`
+	PullRequest *PullRequest `json:"pull_request,omitempty"`
+
+	// Result created — PR создан, merged — PR объединён, closed — PR закрыт, reopened — PR открыт заново,
+	// unchanged — событие уже было обработано, ignored — событие не требует действий
+	Result string `json:"result"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
-	// AssignedReviewers user_id назначенных ревьюверов (0..2)
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	AuthorId          string            `json:"author_id"`
-	CreatedAt         *time.Time        `json:"createdAt"`
-	MergedAt          *time.Time        `json:"mergedAt"`
-	PullRequestId     string            `json:"pull_request_id"`
-	PullRequestName   string            `json:"pull_request_name"`
-	Status            PullRequestStatus `json:"status"`
+	// AssignedReviewers user_id назначенных ревьюверов (0..reviewers_required команды)
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	AuthorId          string   `json:"author_id"`
+
+	// ClosedAt Время закрытия без merge; сбрасывается при повторном открытии
+	ClosedAt  *time.Time `json:"closedAt"`
+	CreatedAt *time.Time `json:"createdAt"`
+
+	// Draft Черновик; ревьюверы назначаются только после /pullRequest/markReady
+	Draft           bool       `json:"draft"`
+	MergedAt        *time.Time `json:"mergedAt"`
+	PullRequestId   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+
+	// Reviews Последний вердикт каждого назначенного ревьювера, который его оставил; снятый ревьювер теряет вердикт
+	Reviews []Review          `json:"reviews"`
+	Status  PullRequestStatus `json:"status"`
 }
 
 // PullRequestStatus defines model for PullRequest.Status.
 type PullRequestStatus string
 
+// PullRequestEvent defines model for PullRequestEvent.
+type PullRequestEvent struct {
+	// Actor Значение заголовка X-Actor запроса, вызвавшего изменение, или api, если заголовок не передан
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"createdAt"`
+	EventId   int64     `json:"event_id"`
+
+	// EventType created — PR создан,
+	// reviewer_assigned — ревьювер назначен (reviewer_id),
+	// reviewer_replaced — ревьювер заменён (old_reviewer_id → new_reviewer_id),
+	// merged — PR объединён,
+	// closed — PR закрыт без merge,
+	// reopened — закрытый PR открыт заново,
+	// ready_for_review — черновик помечен готовым к ревью,
+	// reviewed — ревьювер оставил вердикт (reviewer_id, verdict)
+	EventType     PullRequestEventType `json:"event_type"`
+	NewReviewerId *string              `json:"new_reviewer_id,omitempty"`
+	OldReviewerId *string              `json:"old_reviewer_id,omitempty"`
+	PullRequestId string               `json:"pull_request_id"`
+
+	// Reason Причина назначения или замены: auto — при создании PR или /pullRequest/markReady, manual — /pullRequest/reassign,
+	// user_deactivated — /team/deactivateUsers, reviewers_required — добор до reviewers_required команды,
+	// reopened — замена неактивного ревьювера при /pullRequest/reopen
+	Reason     *string `json:"reason,omitempty"`
+	ReviewerId *string `json:"reviewer_id,omitempty"`
+
+	// Verdict APPROVED — PR одобрен,
+	// CHANGES_REQUESTED — нужны изменения,
+	// COMMENTED — комментарий без решения
+	Verdict *ReviewVerdict `json:"verdict,omitempty"`
+}
+
+// PullRequestEventType created — PR создан,
+// reviewer_assigned — ревьювер назначен (reviewer_id),
+// reviewer_replaced — ревьювер заменён (old_reviewer_id → new_reviewer_id),
+// merged — PR объединён,
+// closed — PR закрыт без merge,
+// reopened — закрытый PR открыт заново,
+// ready_for_review — черновик помечен готовым к ревью,
+// reviewed — ревьювер оставил вердикт (reviewer_id, verdict)
+type PullRequestEventType string
+
 // PullRequestShort defines model for PullRequestShort.
 type PullRequestShort struct {
 	AuthorId        string                 `json:"author_id"`
+	CreatedAt       *time.Time             `json:"createdAt"`
 	PullRequestId   string                 `json:"pull_request_id"`
 	PullRequestName string                 `json:"pull_request_name"`
 	Status          PullRequestShortStatus `json:"status"`
@@ -72,10 +240,61 @@ type PullRequestShort struct {
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// Reassignment defines model for Reassignment.
+type Reassignment struct {
+	NewUserId     string `json:"new_user_id"`
+	OldUserId     string `json:"old_user_id"`
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// Review defines model for Review.
+type Review struct {
+	ReviewerId  string    `json:"reviewer_id"`
+	SubmittedAt time.Time `json:"submittedAt"`
+
+	// Verdict APPROVED — PR одобрен,
+	// CHANGES_REQUESTED — нужны изменения,
+	// COMMENTED — комментарий без решения
+	Verdict ReviewVerdict `json:"verdict"`
+}
+
+// ReviewVerdict APPROVED — PR одобрен,
+// CHANGES_REQUESTED — нужны изменения,
+// COMMENTED — комментарий без решения
+type ReviewVerdict string
+
+// ReviewerStats defines model for ReviewerStats.
+type ReviewerStats struct {
+	// Assignments Сколько раз пользователь назначался ревьювером, включая снятые назначения
+	Assignments int `json:"assignments"`
+
+	// MergedReviewed Объединённые PR, где пользователь ревьювер
+	MergedReviewed int `json:"merged_reviewed"`
+
+	// OpenAssignments Текущие назначения на открытые PR
+	OpenAssignments int `json:"open_assignments"`
+
+	// ReassignedAway Сколько раз пользователя заменили на другого ревьювера
+	ReassignedAway int    `json:"reassigned_away"`
+	TeamName       string `json:"team_name"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+}
+
+// ReviewerStrategy Стратегия выбора ревьюверов:
+// first_n — первые N активных участников,
+// random — случайные участники,
+// round_robin — по кругу с сохраняемым курсором,
+// least_loaded — наименее загруженные открытыми ревью
+type ReviewerStrategy string
+
 // Team defines model for Team.
 type Team struct {
-	Members  []TeamMember `json:"members"`
-	TeamName string       `json:"team_name"`
+	Members []TeamMember `json:"members"`
+
+	// ReviewersRequired Сколько ревьюверов назначается на PR команды; если не задано, используется значение из конфигурации сервиса
+	ReviewersRequired *int   `json:"reviewers_required,omitempty"`
+	TeamName          string `json:"team_name"`
 }
 
 // TeamMember defines model for TeamMember.
@@ -85,6 +304,33 @@ type TeamMember struct {
 	Username string `json:"username"`
 }
 
+// TeamSettings defines model for TeamSettings.
+type TeamSettings struct {
+	// ApprovalsRequired Сколько ревьюверов PR должны одобрить его (APPROVED) перед merge; 0 отключает проверку.
+	// Не больше reviewers_required команды. Если PR назначено меньше ревьюверов,
+	// достаточно одобрения каждого из них. В ответах заполнено всегда;
+	// если не передано в /team/setSettings, текущее значение не меняется.
+	ApprovalsRequired *int `json:"approvals_required,omitempty"`
+
+	// ReviewerStrategy Стратегия выбора ревьюверов:
+	// first_n — первые N активных участников,
+	// random — случайные участники,
+	// round_robin — по кругу с сохраняемым курсором,
+	// least_loaded — наименее загруженные открытыми ревью
+	ReviewerStrategy ReviewerStrategy `json:"reviewer_strategy"`
+	TeamName         string           `json:"team_name"`
+}
+
+// TeamStats Суммы ReviewerStats по участникам команды
+type TeamStats struct {
+	Assignments     int    `json:"assignments"`
+	Members         int    `json:"members"`
+	MergedReviewed  int    `json:"merged_reviewed"`
+	OpenAssignments int    `json:"open_assignments"`
+	ReassignedAway  int    `json:"reassigned_away"`
+	TeamName        string `json:"team_name"`
+}
+
 // User defines model for User.
 type User struct {
 	IsActive bool   `json:"is_active"`
@@ -93,19 +339,167 @@ type User struct {
 	Username string `json:"username"`
 }
 
+// WebhookEventType pull_request.created — PR создан и получил ревьюверов (черновик — без ревьюверов),
+// pull_request.ready_for_review — черновик помечен готовым и получил ревьюверов,
+// pull_request.reassigned — ревьювер заменён (вручную, при деактивации или повторном открытии),
+// pull_request.reviewed — ревьювер оставил вердикт,
+// pull_request.merged — PR объединён,
+// pull_request.closed — PR закрыт без merge,
+// pull_request.reopened — закрытый PR открыт заново,
+// user.deactivated — пользователь деактивирован
+type WebhookEventType string
+
+// WebhookPayload Тело запроса к получателю вебхука. Подпись HMAC-SHA256 тела с секретом подписки
+// передаётся в заголовке X-Webhook-Signature-256 в виде sha256=<hex>.
+type WebhookPayload struct {
+	Actor string `json:"actor"`
+
+	// Event pull_request.created — PR создан и получил ревьюверов (черновик — без ревьюверов),
+	// pull_request.ready_for_review — черновик помечен готовым и получил ревьюверов,
+	// pull_request.reassigned — ревьювер заменён (вручную, при деактивации или повторном открытии),
+	// pull_request.reviewed — ревьювер оставил вердикт,
+	// pull_request.merged — PR объединён,
+	// pull_request.closed — PR закрыт без merge,
+	// pull_request.reopened — закрытый PR открыт заново,
+	// user.deactivated — пользователь деактивирован
+	Event         WebhookEventType `json:"event"`
+	NewReviewerId *string          `json:"new_reviewer_id,omitempty"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+	OldReviewerId *string          `json:"old_reviewer_id,omitempty"`
+	PullRequest   *PullRequest     `json:"pull_request,omitempty"`
+	Reason        *string          `json:"reason,omitempty"`
+	User          *User            `json:"user,omitempty"`
+}
+
+// WebhookSubscription defines model for WebhookSubscription.
+type WebhookSubscription struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Events События, на которые подписан получатель; пустой список — все события
+	Events         []WebhookEventType `json:"events"`
+	SubscriptionId string             `json:"subscription_id"`
+	Url            string             `json:"url"`
+}
+
+// CursorQuery defines model for CursorQuery.
+type CursorQuery = string
+
+// FromQuery defines model for FromQuery.
+type FromQuery = time.Time
+
+// LimitQuery defines model for LimitQuery.
+type LimitQuery = int
+
+// SortQuery defines model for SortQuery.
+type SortQuery string
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
+// ToQuery defines model for ToQuery.
+type ToQuery = time.Time
+
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
+// PostIntegrationsGithubSetUserJSONBody defines parameters for PostIntegrationsGithubSetUser.
+type PostIntegrationsGithubSetUserJSONBody struct {
+	GithubLogin string `json:"github_login"`
+	UserId      string `json:"user_id"`
+}
+
+// PostIntegrationsGithubWebhookJSONBody defines parameters for PostIntegrationsGithubWebhook.
+type PostIntegrationsGithubWebhookJSONBody map[string]interface{}
+
+// PostIntegrationsGithubWebhookParams defines parameters for PostIntegrationsGithubWebhook.
+type PostIntegrationsGithubWebhookParams struct {
+	// XGitHubEvent Тип события GitHub
+	XGitHubEvent *string `json:"X-GitHub-Event,omitempty"`
+
+	// XHubSignature256 HMAC-SHA256 тела с секретом вебхука в виде sha256=<hex>
+	XHubSignature256 *string `json:"X-Hub-Signature-256,omitempty"`
+}
+
+// PostIntegrationsGitlabWebhookJSONBody defines parameters for PostIntegrationsGitlabWebhook.
+type PostIntegrationsGitlabWebhookJSONBody map[string]interface{}
+
+// PostIntegrationsGitlabWebhookParams defines parameters for PostIntegrationsGitlabWebhook.
+type PostIntegrationsGitlabWebhookParams struct {
+	// XGitlabEvent Тип события GitLab
+	XGitlabEvent *string `json:"X-Gitlab-Event,omitempty"`
+
+	// XGitlabToken Секретный токен, заданный в настройках вебхука
+	XGitlabToken *string `json:"X-Gitlab-Token,omitempty"`
+}
+
+// PostPullRequestCloseJSONBody defines parameters for PostPullRequestClose.
+type PostPullRequestCloseJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
 // PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
 type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
+	AuthorId string `json:"author_id"`
+
+	// Draft Создать черновик без ревьюверов
+	Draft           *bool  `json:"draft,omitempty"`
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 }
 
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestHistoryParams defines parameters for GetPullRequestHistory.
+type GetPullRequestHistoryParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestListParams defines parameters for GetPullRequestList.
+type GetPullRequestListParams struct {
+	// AuthorId Только PR этого автора
+	AuthorId *string `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// TeamName Только PR, автор которых состоит в этой команде
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// Status Только PR в этом статусе
+	Status *GetPullRequestListParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// ReviewerId Только PR, на которые назначен этот ревьювер
+	ReviewerId *string `form:"reviewer_id,omitempty" json:"reviewer_id,omitempty"`
+
+	// Name Подстрока названия PR без учёта регистра
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// CreatedAfter Только PR, созданные не раньше этого момента
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Только PR, созданные раньше этого момента
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// Sort Порядок по createdAt, затем по pull_request_id
+	Sort *SortQuery `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor Значение next_cursor из предыдущего ответа; фильтры и sort должны совпадать
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Размер страницы
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetPullRequestListParamsStatus defines parameters for GetPullRequestList.
+type GetPullRequestListParamsStatus string
+
+// PostPullRequestMarkReadyJSONBody defines parameters for PostPullRequestMarkReady.
+type PostPullRequestMarkReadyJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
 type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
@@ -117,62 +511,254 @@ type PostPullRequestReassignJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// PostPullRequestReopenJSONBody defines parameters for PostPullRequestReopen.
+type PostPullRequestReopenJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestReviewJSONBody defines parameters for PostPullRequestReview.
+type PostPullRequestReviewJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+
+	// Verdict APPROVED — PR одобрен,
+	// CHANGES_REQUESTED — нужны изменения,
+	// COMMENTED — комментарий без решения
+	Verdict ReviewVerdict `json:"verdict"`
+}
+
+// GetStatsReviewersParams defines parameters for GetStatsReviewers.
+type GetStatsReviewersParams struct {
+	// From Начало окна (включительно)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец окна (не включительно)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// TeamName Ограничить статистику одной командой
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+}
+
+// GetStatsTeamsParams defines parameters for GetStatsTeams.
+type GetStatsTeamsParams struct {
+	// From Начало окна (включительно)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец окна (не включительно)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// PostTeamDeactivateUsersJSONBody defines parameters for PostTeamDeactivateUsers.
+type PostTeamDeactivateUsersJSONBody struct {
+	TeamName string   `json:"team_name"`
+	UserIds  []string `json:"user_ids"`
+}
+
 // GetTeamGetParams defines parameters for GetTeamGet.
 type GetTeamGetParams struct {
 	// TeamName Уникальное имя команды
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
+// GetTeamGetSettingsParams defines parameters for GetTeamGetSettings.
+type GetTeamGetSettingsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamSetReviewersRequiredJSONBody defines parameters for PostTeamSetReviewersRequired.
+type PostTeamSetReviewersRequiredJSONBody struct {
+	ReviewersRequired int    `json:"reviewers_required"`
+	TeamName          string `json:"team_name"`
+}
+
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
 type GetUsersGetReviewParams struct {
 	// UserId Идентификатор пользователя
 	UserId UserIdQuery `form:"user_id" json:"user_id"`
+
+	// Status Только PR в этом статусе
+	Status *GetUsersGetReviewParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// CreatedAfter Только PR, созданные не раньше этого момента
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Только PR, созданные раньше этого момента
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// Sort Порядок по createdAt, затем по pull_request_id
+	Sort *SortQuery `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor Значение next_cursor из предыдущего ответа; фильтры и sort должны совпадать
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Размер страницы
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// GetUsersGetReviewParamsStatus defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsStatus string
+
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
 type PostUsersSetIsActiveJSONBody struct {
 	IsActive bool   `json:"is_active"`
 	UserId   string `json:"user_id"`
 }
 
+// PostWebhooksAddJSONBody defines parameters for PostWebhooksAdd.
+type PostWebhooksAddJSONBody struct {
+	// Events Фильтр событий; если не задан, доставляются все
+	Events *[]WebhookEventType `json:"events,omitempty"`
+
+	// Secret Общий секрет для подписи тела запроса
+	Secret string `json:"secret"`
+
+	// Url Абсолютный http(s) URL получателя
+	Url string `json:"url"`
+}
+
+// PostWebhooksDeleteJSONBody defines parameters for PostWebhooksDelete.
+type PostWebhooksDeleteJSONBody struct {
+	SubscriptionId string `json:"subscription_id"`
+}
+
+// PostIntegrationsGithubSetUserJSONRequestBody defines body for PostIntegrationsGithubSetUser for application/json ContentType.
+type PostIntegrationsGithubSetUserJSONRequestBody PostIntegrationsGithubSetUserJSONBody
+
+// PostIntegrationsGithubWebhookJSONRequestBody defines body for PostIntegrationsGithubWebhook for application/json ContentType.
+type PostIntegrationsGithubWebhookJSONRequestBody PostIntegrationsGithubWebhookJSONBody
+
+// PostIntegrationsGitlabWebhookJSONRequestBody defines body for PostIntegrationsGitlabWebhook for application/json ContentType.
+type PostIntegrationsGitlabWebhookJSONRequestBody PostIntegrationsGitlabWebhookJSONBody
+
+// PostPullRequestCloseJSONRequestBody defines body for PostPullRequestClose for application/json ContentType.
+type PostPullRequestCloseJSONRequestBody PostPullRequestCloseJSONBody
+
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
 type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
 
+// PostPullRequestMarkReadyJSONRequestBody defines body for PostPullRequestMarkReady for application/json ContentType.
+type PostPullRequestMarkReadyJSONRequestBody PostPullRequestMarkReadyJSONBody
+
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
 // PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
 type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 
+// PostPullRequestReopenJSONRequestBody defines body for PostPullRequestReopen for application/json ContentType.
+type PostPullRequestReopenJSONRequestBody PostPullRequestReopenJSONBody
+
+// PostPullRequestReviewJSONRequestBody defines body for PostPullRequestReview for application/json ContentType.
+type PostPullRequestReviewJSONRequestBody PostPullRequestReviewJSONBody
+
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamDeactivateUsersJSONRequestBody defines body for PostTeamDeactivateUsers for application/json ContentType.
+type PostTeamDeactivateUsersJSONRequestBody PostTeamDeactivateUsersJSONBody
+
+// PostTeamSetReviewersRequiredJSONRequestBody defines body for PostTeamSetReviewersRequired for application/json ContentType.
+type PostTeamSetReviewersRequiredJSONRequestBody PostTeamSetReviewersRequiredJSONBody
+
+// PostTeamSetSettingsJSONRequestBody defines body for PostTeamSetSettings for application/json ContentType.
+type PostTeamSetSettingsJSONRequestBody = TeamSettings
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
 
+// PostWebhooksAddJSONRequestBody defines body for PostWebhooksAdd for application/json ContentType.
+type PostWebhooksAddJSONRequestBody PostWebhooksAddJSONBody
+
+// PostWebhooksDeleteJSONRequestBody defines body for PostWebhooksDelete for application/json ContentType.
+type PostWebhooksDeleteJSONRequestBody PostWebhooksDeleteJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+	// Проверка, что процесс жив
+	// (GET /health/live)
+	GetHealthLive(w http.ResponseWriter, r *http.Request)
+	// Проверка готовности принимать запросы (доступность БД)
+	// (GET /health/ready)
+	GetHealthReady(w http.ResponseWriter, r *http.Request)
+	// Сопоставить логин GitHub пользователю
+	// (POST /integrations/github/setUser)
+	PostIntegrationsGithubSetUser(w http.ResponseWriter, r *http.Request)
+	// Приём вебхуков GitHub pull_request
+	// (POST /integrations/github/webhook)
+	PostIntegrationsGithubWebhook(w http.ResponseWriter, r *http.Request, params PostIntegrationsGithubWebhookParams)
+	// Приём вебхуков GitLab Merge Request Hook
+	// (POST /integrations/gitlab/webhook)
+	PostIntegrationsGitlabWebhook(w http.ResponseWriter, r *http.Request, params PostIntegrationsGitlabWebhookParams)
+	// Закрыть PR без merge (идемпотентная операция)
+	// (POST /pullRequest/close)
+	PostPullRequestClose(w http.ResponseWriter, r *http.Request)
+	// Создать PR и автоматически назначить ревьюверов из команды автора (до reviewers_required команды)
 	// (POST /pullRequest/create)
 	PostPullRequestCreate(w http.ResponseWriter, r *http.Request)
+	// Получить PR
+	// (GET /pullRequest/get)
+	GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams)
+	// История изменений PR (назначения, замены, merge, закрытие)
+	// (GET /pullRequest/history)
+	GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams)
+	// Найти PR по фильтрам
+	// (GET /pullRequest/list)
+	GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams)
+	// Пометить черновик готовым к ревью и назначить ревьюверов (идемпотентная операция)
+	// (POST /pullRequest/markReady)
+	PostPullRequestMarkReady(w http.ResponseWriter, r *http.Request)
 	// Пометить PR как MERGED (идемпотентная операция)
 	// (POST /pullRequest/merge)
 	PostPullRequestMerge(w http.ResponseWriter, r *http.Request)
 	// Переназначить конкретного ревьювера на другого из его команды
 	// (POST /pullRequest/reassign)
 	PostPullRequestReassign(w http.ResponseWriter, r *http.Request)
+	// Открыть закрытый PR заново (идемпотентная операция)
+	// (POST /pullRequest/reopen)
+	PostPullRequestReopen(w http.ResponseWriter, r *http.Request)
+	// Оставить вердикт ревьювера по PR
+	// (POST /pullRequest/review)
+	PostPullRequestReview(w http.ResponseWriter, r *http.Request)
+	// Статистика назначений по ревьюверам
+	// (GET /stats/reviewers)
+	GetStatsReviewers(w http.ResponseWriter, r *http.Request, params GetStatsReviewersParams)
+	// Статистика назначений по командам
+	// (GET /stats/teams)
+	GetStatsTeams(w http.ResponseWriter, r *http.Request, params GetStatsTeamsParams)
 	// Создать команду с участниками (создаёт/обновляет пользователей)
 	// (POST /team/add)
 	PostTeamAdd(w http.ResponseWriter, r *http.Request)
+	// Деактивировать участников команды и переназначить их открытые ревью
+	// (POST /team/deactivateUsers)
+	PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request)
 	// Получить команду с участниками
 	// (GET /team/get)
 	GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams)
+	// Получить настройки команды
+	// (GET /team/getSettings)
+	GetTeamGetSettings(w http.ResponseWriter, r *http.Request, params GetTeamGetSettingsParams)
+	// Изменить число ревьюверов, назначаемых на PR команды
+	// (POST /team/setReviewersRequired)
+	PostTeamSetReviewersRequired(w http.ResponseWriter, r *http.Request)
+	// Изменить настройки команды
+	// (POST /team/setSettings)
+	PostTeamSetSettings(w http.ResponseWriter, r *http.Request)
 	// Получить PR'ы, где пользователь назначен ревьювером
 	// (GET /users/getReview)
 	GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams)
 	// Установить флаг активности пользователя
 	// (POST /users/setIsActive)
 	PostUsersSetIsActive(w http.ResponseWriter, r *http.Request)
+	// Подписать URL на события PR и назначений
+	// (POST /webhooks/add)
+	PostWebhooksAdd(w http.ResponseWriter, r *http.Request)
+	// Удалить подписку вместе с недоставленными событиями
+	// (POST /webhooks/delete)
+	PostWebhooksDelete(w http.ResponseWriter, r *http.Request)
+	// Список подписок (без секретов)
+	// (GET /webhooks/list)
+	GetWebhooksList(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -184,11 +770,11 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// PostPullRequestCreate operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+// GetHealthLive operation middleware
+func (siw *ServerInterfaceWrapper) GetHealthLive(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestCreate(w, r)
+		siw.Handler.GetHealthLive(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -198,11 +784,11 @@ func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter,
 	handler.ServeHTTP(w, r)
 }
 
-// PostPullRequestMerge operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+// GetHealthReady operation middleware
+func (siw *ServerInterfaceWrapper) GetHealthReady(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestMerge(w, r)
+		siw.Handler.GetHealthReady(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -212,11 +798,11 @@ func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// PostPullRequestReassign operation middleware
-func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+// PostIntegrationsGithubSetUser operation middleware
+func (siw *ServerInterfaceWrapper) PostIntegrationsGithubSetUser(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostPullRequestReassign(w, r)
+		siw.Handler.PostIntegrationsGithubSetUser(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -226,11 +812,56 @@ func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter
 	handler.ServeHTTP(w, r)
 }
 
-// PostTeamAdd operation middleware
-func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+// PostIntegrationsGithubWebhook operation middleware
+func (siw *ServerInterfaceWrapper) PostIntegrationsGithubWebhook(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostIntegrationsGithubWebhookParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-GitHub-Event" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-GitHub-Event")]; found {
+		var XGitHubEvent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-GitHub-Event", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-GitHub-Event", valueList[0], &XGitHubEvent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-GitHub-Event", Err: err})
+			return
+		}
+
+		params.XGitHubEvent = &XGitHubEvent
+
+	}
+
+	// ------------- Optional header parameter "X-Hub-Signature-256" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Hub-Signature-256")]; found {
+		var XHubSignature256 string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Hub-Signature-256", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Hub-Signature-256", valueList[0], &XHubSignature256, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Hub-Signature-256", Err: err})
+			return
+		}
+
+		params.XHubSignature256 = &XHubSignature256
+
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostTeamAdd(w, r)
+		siw.Handler.PostIntegrationsGithubWebhook(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -240,31 +871,118 @@ func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// GetTeamGet operation middleware
-func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
+// PostIntegrationsGitlabWebhook operation middleware
+func (siw *ServerInterfaceWrapper) PostIntegrationsGitlabWebhook(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetTeamGetParams
+	var params PostIntegrationsGitlabWebhookParams
 
-	// ------------- Required query parameter "team_name" -------------
+	headers := r.Header
 
-	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+	// ------------- Optional header parameter "X-Gitlab-Event" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Gitlab-Event")]; found {
+		var XGitlabEvent string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Gitlab-Event", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Gitlab-Event", valueList[0], &XGitlabEvent, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Gitlab-Event", Err: err})
+			return
+		}
+
+		params.XGitlabEvent = &XGitlabEvent
+
+	}
+
+	// ------------- Optional header parameter "X-Gitlab-Token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Gitlab-Token")]; found {
+		var XGitlabToken string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-Gitlab-Token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-Gitlab-Token", valueList[0], &XGitlabToken, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-Gitlab-Token", Err: err})
+			return
+		}
+
+		params.XGitlabToken = &XGitlabToken
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostIntegrationsGitlabWebhook(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestClose operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestClose(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestCreate operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestCreate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPullRequestGet operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestGet(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestGetParams
+
+	// ------------- Required query parameter "pull_request_id" -------------
+
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTeamGet(w, r, params)
+		siw.Handler.GetPullRequestGet(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -274,31 +992,31 @@ func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// GetUsersGetReview operation middleware
-func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *http.Request) {
+// GetPullRequestHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetUsersGetReviewParams
+	var params GetPullRequestHistoryParams
 
-	// ------------- Required query parameter "user_id" -------------
+	// ------------- Required query parameter "pull_request_id" -------------
 
-	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+	if paramValue := r.URL.Query().Get("pull_request_id"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "pull_request_id"})
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	err = runtime.BindQueryParameter("form", true, true, "pull_request_id", r.URL.Query(), &params.PullRequestId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pull_request_id", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUsersGetReview(w, r, params)
+		siw.Handler.GetPullRequestHistory(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -308,10 +1026,463 @@ func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// PostUsersSetIsActive operation middleware
-func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+// GetPullRequestList operation middleware
+func (siw *ServerInterfaceWrapper) GetPullRequestList(w http.ResponseWriter, r *http.Request) {
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPullRequestListParams
+
+	// ------------- Optional query parameter "author_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author_id", r.URL.Query(), &params.AuthorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "reviewer_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reviewer_id", r.URL.Query(), &params.ReviewerId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "reviewer_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", r.URL.Query(), &params.Name)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", r.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", r.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPullRequestList(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestMarkReady operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestMarkReady(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestMarkReady(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestMerge operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestMerge(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestReassign operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReassign(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestReopen operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReopen(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReopen(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostPullRequestReview operation middleware
+func (siw *ServerInterfaceWrapper) PostPullRequestReview(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostPullRequestReview(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatsReviewers operation middleware
+func (siw *ServerInterfaceWrapper) GetStatsReviewers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsReviewersParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "team_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatsReviewers(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetStatsTeams operation middleware
+func (siw *ServerInterfaceWrapper) GetStatsTeams(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetStatsTeamsParams
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetStatsTeams(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamAdd operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamAdd(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamDeactivateUsers operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamDeactivateUsers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTeamGet operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGet(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGet(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTeamGetSettings operation middleware
+func (siw *ServerInterfaceWrapper) GetTeamGetSettings(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTeamGetSettingsParams
+
+	// ------------- Required query parameter "team_name" -------------
+
+	if paramValue := r.URL.Query().Get("team_name"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "team_name"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "team_name", r.URL.Query(), &params.TeamName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "team_name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTeamGetSettings(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamSetReviewersRequired operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamSetReviewersRequired(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamSetReviewersRequired(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostTeamSetSettings operation middleware
+func (siw *ServerInterfaceWrapper) PostTeamSetSettings(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTeamSetSettings(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsersGetReview operation middleware
+func (siw *ServerInterfaceWrapper) GetUsersGetReview(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsersGetReviewParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", r.URL.Query(), &params.CreatedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", r.URL.Query(), &params.CreatedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "created_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsersGetReview(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostUsersSetIsActive operation middleware
+func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.PostUsersSetIsActive(w, r)
 	}))
 
@@ -322,6 +1493,48 @@ func (siw *ServerInterfaceWrapper) PostUsersSetIsActive(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
+// PostWebhooksAdd operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksAdd(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksAdd(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostWebhooksDelete operation middleware
+func (siw *ServerInterfaceWrapper) PostWebhooksDelete(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostWebhooksDelete(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWebhooksList operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhooksList(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhooksList(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -442,13 +1655,34 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	m.HandleFunc("GET "+options.BaseURL+"/health/live", wrapper.GetHealthLive)
+	m.HandleFunc("GET "+options.BaseURL+"/health/ready", wrapper.GetHealthReady)
+	m.HandleFunc("POST "+options.BaseURL+"/integrations/github/setUser", wrapper.PostIntegrationsGithubSetUser)
+	m.HandleFunc("POST "+options.BaseURL+"/integrations/github/webhook", wrapper.PostIntegrationsGithubWebhook)
+	m.HandleFunc("POST "+options.BaseURL+"/integrations/gitlab/webhook", wrapper.PostIntegrationsGitlabWebhook)
+	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/close", wrapper.PostPullRequestClose)
 	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/create", wrapper.PostPullRequestCreate)
+	m.HandleFunc("GET "+options.BaseURL+"/pullRequest/get", wrapper.GetPullRequestGet)
+	m.HandleFunc("GET "+options.BaseURL+"/pullRequest/history", wrapper.GetPullRequestHistory)
+	m.HandleFunc("GET "+options.BaseURL+"/pullRequest/list", wrapper.GetPullRequestList)
+	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/markReady", wrapper.PostPullRequestMarkReady)
 	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/merge", wrapper.PostPullRequestMerge)
 	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/reassign", wrapper.PostPullRequestReassign)
+	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/reopen", wrapper.PostPullRequestReopen)
+	m.HandleFunc("POST "+options.BaseURL+"/pullRequest/review", wrapper.PostPullRequestReview)
+	m.HandleFunc("GET "+options.BaseURL+"/stats/reviewers", wrapper.GetStatsReviewers)
+	m.HandleFunc("GET "+options.BaseURL+"/stats/teams", wrapper.GetStatsTeams)
 	m.HandleFunc("POST "+options.BaseURL+"/team/add", wrapper.PostTeamAdd)
+	m.HandleFunc("POST "+options.BaseURL+"/team/deactivateUsers", wrapper.PostTeamDeactivateUsers)
 	m.HandleFunc("GET "+options.BaseURL+"/team/get", wrapper.GetTeamGet)
+	m.HandleFunc("GET "+options.BaseURL+"/team/getSettings", wrapper.GetTeamGetSettings)
+	m.HandleFunc("POST "+options.BaseURL+"/team/setReviewersRequired", wrapper.PostTeamSetReviewersRequired)
+	m.HandleFunc("POST "+options.BaseURL+"/team/setSettings", wrapper.PostTeamSetSettings)
 	m.HandleFunc("GET "+options.BaseURL+"/users/getReview", wrapper.GetUsersGetReview)
 	m.HandleFunc("POST "+options.BaseURL+"/users/setIsActive", wrapper.PostUsersSetIsActive)
+	m.HandleFunc("POST "+options.BaseURL+"/webhooks/add", wrapper.PostWebhooksAdd)
+	m.HandleFunc("POST "+options.BaseURL+"/webhooks/delete", wrapper.PostWebhooksDelete)
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks/list", wrapper.GetWebhooksList)
 
 	return m
 }
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"pr-reviewer/internal/logging"
+	"pr-reviewer/internal/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader передаёт идентификатор запроса между клиентом, сервисом и логами
+const RequestIDHeader = "X-Request-ID"
+
+// ActorHeader указывает инициатора изменений для журнала PR
+const ActorHeader = "X-Actor"
+
+// maxRequestIDLength ограничивает идентификатор от клиента, чтобы он не раздувал логи
+const maxRequestIDLength = 128
+
+// maxActorLength ограничивает значение X-Actor, попадающее в журнал PR
+const maxActorLength = 128
+
+// WithRequestTimeout ограничивает время обработки запроса: по истечении timeout
+// контекст запроса отменяется, и незавершённые запросы к БД прерываются.
+// Нулевой timeout оставляет только отмену при разрыве соединения клиентом.
+func WithRequestTimeout(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WithRequestID берёт идентификатор запроса из заголовка X-Request-ID или создаёт новый,
+// сохраняет его в контексте для логов и возвращает клиенту в том же заголовке.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// WithActor передаёт значение заголовка X-Actor в контекст запроса, чтобы сервис
+// записал его в журнал PR. Без заголовка сервис использует service.DefaultActor.
+func WithActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(ActorHeader)
+		if actor == "" || len(actor) > maxActorLength {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(service.WithActor(r.Context(), actor)))
+	})
+}
+
+// WithAccessLog пишет в logger строку на каждый запрос с кодом ответа и длительностью.
+// Должен стоять внутри WithRequestID, чтобы запись содержала request_id.
+func WithAccessLog(next http.Handler, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		logger.LogAttrs(r.Context(), slog.LevelInfo, "HTTP-запрос",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", sw.status),
+			slog.Int("bytes", sw.bytes),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
+
+// statusWriter запоминает код ответа и размер тела
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
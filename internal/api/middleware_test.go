@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"pr-reviewer/internal/logging"
+	"pr-reviewer/internal/service"
+	"testing"
+	"time"
+)
+
+func TestWithRequestTimeoutSetsDeadline(t *testing.T) {
+	var deadline time.Time
+	var ok bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok = r.Context().Deadline()
+	})
+
+	start := time.Now()
+	WithRequestTimeout(next, time.Second).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !ok {
+		t.Fatal("У контекста запроса должен быть дедлайн")
+	}
+	if deadline.Before(start) || deadline.After(start.Add(time.Second+100*time.Millisecond)) {
+		t.Fatalf("Неожиданный дедлайн %v", deadline)
+	}
+}
+
+func TestWithRequestTimeoutDisabled(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			t.Fatal("При нулевом таймауте дедлайн не должен устанавливаться")
+		}
+	})
+
+	WithRequestTimeout(next, 0).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestWithRequestID(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	})
+	handler := WithRequestID(next)
+
+	// Идентификатор клиента сохраняется
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen != "req-42" || rec.Header().Get(RequestIDHeader) != "req-42" {
+		t.Fatalf("Ожидался request ID req-42, в контексте %q, в ответе %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+
+	// Без заголовка создаётся новый
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if seen == "" || seen == "req-42" || rec.Header().Get(RequestIDHeader) != seen {
+		t.Fatalf("Ожидался новый request ID, в контексте %q, в ответе %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestWithAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, slog.LevelInfo, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	req := httptest.NewRequest("POST", "/team/add", nil)
+	req.Header.Set(RequestIDHeader, "req-7")
+	WithRequestID(WithAccessLog(next, logger)).ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Ошибка разбора записи лога %q: %v", buf.String(), err)
+	}
+	if entry["status"] != float64(http.StatusTeapot) || entry["path"] != "/team/add" || entry["request_id"] != "req-7" {
+		t.Fatalf("Неверная запись access-лога: %v", entry)
+	}
+}
+
+func TestWithActor(t *testing.T) {
+	var actor string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = service.Actor(r.Context())
+	})
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set(ActorHeader, "alice")
+	WithActor(next).ServeHTTP(httptest.NewRecorder(), req)
+	if actor != "alice" {
+		t.Fatalf("Ожидался инициатор alice, получен %q", actor)
+	}
+
+	WithActor(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+	if actor != service.DefaultActor {
+		t.Fatalf("Без заголовка ожидался %q, получен %q", service.DefaultActor, actor)
+	}
+}
@@ -1,21 +1,107 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/integrations"
+	"pr-reviewer/internal/integrations/github"
+	"pr-reviewer/internal/integrations/gitlab"
+	"pr-reviewer/internal/logging"
 	"pr-reviewer/internal/models"
 	"pr-reviewer/internal/service"
+	"time"
 )
 
+// defaultReadinessTimeout ограничивает проверку БД в /health/ready, чтобы проба
+// не зависала дольше таймаута самого оркестратора
+const defaultReadinessTimeout = 2 * time.Second
+
+// maxIntegrationBody ограничивает тело входящего вебхука: подпись проверяется по телу целиком
+const maxIntegrationBody = 5 << 20
+
+// statusClientClosedRequest — нестандартный статус nginx для запроса, прерванного клиентом
+const statusClientClosedRequest = 499
+
+// Options задаёт параметры сервера; нулевое значение включает все эндпоинты
+type Options struct {
+	// ReadinessTimeout ограничивает проверку БД в /health/ready; 0 — значение по умолчанию
+	ReadinessTimeout time.Duration
+	// DisableStats отключает /stats/*
+	DisableStats bool
+	// DisableTeamDeactivation отключает /team/deactivateUsers
+	DisableTeamDeactivation bool
+	// GitHubWebhookSecret включает /integrations/github/*; пустая строка их отключает
+	GitHubWebhookSecret string
+	// GitLabWebhookToken включает /integrations/gitlab/webhook; пустая строка его отключает
+	GitLabWebhookToken string
+	// GitLabReviewers записывает назначенных ревьюверов в MR GitLab; nil отключает запись
+	GitLabReviewers gitlab.ReviewerClient
+	// Logger получает ошибки обработки запросов; nil — slog.Default()
+	Logger *slog.Logger
+}
+
 // Server реализует сгенерированный ServerInterface
 type Server struct {
 	service *service.Service
+	opts    Options
+	// github задан, если интеграция с GitHub включена
+	github *github.Adapter
+	// gitlab задан, если интеграция с GitLab включена
+	gitlab *gitlab.Adapter
 }
 
 // NewServer создает сервер с внедрённым сервисом.
-func NewServer(svc *service.Service) *Server {
-	return &Server{service: svc}
+func NewServer(svc *service.Service, opts Options) *Server {
+	if opts.ReadinessTimeout <= 0 {
+		opts.ReadinessTimeout = defaultReadinessTimeout
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	s := &Server{service: svc, opts: opts}
+	if opts.GitHubWebhookSecret != "" {
+		s.github = github.NewAdapter(svc, opts.GitHubWebhookSecret)
+	}
+	if opts.GitLabWebhookToken != "" {
+		s.gitlab = gitlab.NewAdapter(svc, opts.GitLabWebhookToken, gitlab.Options{
+			Reviewers: opts.GitLabReviewers,
+			Logger:    opts.Logger,
+		})
+	}
+	return s
+}
+
+// Wait дожидается фоновых задач обработчиков (записи ревьюверов в GitLab);
+// вызывается после остановки HTTP-сервера
+func (s *Server) Wait() {
+	if s.gitlab != nil {
+		s.gitlab.Wait()
+	}
+}
+
+// GetHealthLive сообщает, что процесс жив; БД не проверяется
+func (s *Server) GetHealthLive(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.HealthStatus{Status: models.Ok})
+}
+
+// GetHealthReady проверяет доступность БД и возвращает версию миграций
+func (s *Server) GetHealthReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.ReadinessTimeout)
+	defer cancel()
+
+	version, err := s.service.CheckReadiness(ctx)
+	if err != nil {
+		s.opts.Logger.LogAttrs(r.Context(), slog.LevelWarn, "Проверка готовности не пройдена", logging.Err(err))
+		writeJSON(w, http.StatusServiceUnavailable, models.HealthStatus{Status: models.Unavailable})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.HealthStatus{Status: models.Ok, MigrationVersion: &version})
 }
 
 // PostTeamAdd создает команду с участниками
@@ -26,8 +112,8 @@ func (s *Server) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.service.CreateTeam(&team); err != nil {
-		handleError(w, err)
+	if err := s.service.CreateTeam(r.Context(), &team); err != nil {
+		s.handleError(w, r, err)
 		return
 	}
 
@@ -36,15 +122,90 @@ func (s *Server) PostTeamAdd(w http.ResponseWriter, r *http.Request) {
 
 // GetTeamGet получает команду с участниками
 func (s *Server) GetTeamGet(w http.ResponseWriter, r *http.Request, params GetTeamGetParams) {
-	team, err := s.service.GetTeam(params.TeamName)
+	team, err := s.service.GetTeam(r.Context(), params.TeamName)
 	if err != nil {
-		handleError(w, err)
+		s.handleError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, team)
 }
 
+// GetTeamGetSettings получает настройки команды
+func (s *Server) GetTeamGetSettings(w http.ResponseWriter, r *http.Request, params GetTeamGetSettingsParams) {
+	settings, err := s.service.GetTeamSettings(r.Context(), params.TeamName)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// PostTeamSetSettings изменяет настройки команды
+func (s *Server) PostTeamSetSettings(w http.ResponseWriter, r *http.Request) {
+	var req models.TeamSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	settings, err := s.service.SetTeamSettings(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.TeamSettings{"settings": settings})
+}
+
+// PostTeamSetReviewersRequired изменяет число ревьюверов, назначаемых на PR команды
+func (s *Server) PostTeamSetReviewersRequired(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TeamName          string `json:"team_name"`
+		ReviewersRequired int    `json:"reviewers_required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	team, err := s.service.SetTeamReviewersRequired(r.Context(), req.TeamName, req.ReviewersRequired)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.Team{"team": team})
+}
+
+// PostTeamDeactivateUsers деактивирует участников команды и переназначает их открытые ревью
+func (s *Server) PostTeamDeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	if s.opts.DisableTeamDeactivation {
+		writeDisabled(w)
+		return
+	}
+
+	var req struct {
+		TeamName string   `json:"team_name"`
+		UserIds  []string `json:"user_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	report, err := s.service.DeactivateTeamUsers(r.Context(), req.TeamName, req.UserIds)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.DeactivationReport{"report": report})
+}
+
 // PostUsersSetIsActive устанавливает флаг активности пользователя
 func (s *Server) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -57,21 +218,23 @@ func (s *Server) PostUsersSetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.service.SetUserActive(req.UserId, req.IsActive)
+	user, err := s.service.SetUserActive(r.Context(), req.UserId, req.IsActive)
 	if err != nil {
-		handleError(w, err)
+		s.handleError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]*models.User{"user": user})
 }
 
-// PostPullRequestCreate создает PR и автоматически назначает до 2 ревьюверов
+// PostPullRequestCreate создает PR и автоматически назначает ревьюверов из команды автора;
+// черновик создаётся без ревьюверов
 func (s *Server) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestId   string `json:"pull_request_id"`
 		PullRequestName string `json:"pull_request_name"`
 		AuthorId        string `json:"author_id"`
+		Draft           bool   `json:"draft"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -79,15 +242,39 @@ func (s *Server) PostPullRequestCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := s.service.CreatePullRequest(req.PullRequestId, req.PullRequestName, req.AuthorId)
+	create := s.service.CreatePullRequest
+	if req.Draft {
+		create = s.service.CreateDraftPullRequest
+	}
+	pr, err := create(r.Context(), req.PullRequestId, req.PullRequestName, req.AuthorId)
 	if err != nil {
-		handleError(w, err)
+		s.handleError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, map[string]*models.PullRequest{"pull_request": pr})
 }
 
+// PostPullRequestMarkReady помечает черновик готовым к ревью и назначает ревьюверов
+func (s *Server) PostPullRequestMarkReady(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string `json:"pull_request_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	pr, err := s.service.MarkPullRequestReady(r.Context(), req.PullRequestId)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.PullRequest{"pull_request": pr})
+}
+
 // PostPullRequestMerge помечает PR как MERGED
 func (s *Server) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -99,21 +286,82 @@ func (s *Server) PostPullRequestMerge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := s.service.MergePullRequest(req.PullRequestId)
+	pr, err := s.service.MergePullRequest(r.Context(), req.PullRequestId)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.PullRequest{"pull_request": pr})
+}
+
+// PostPullRequestClose закрывает PR без merge
+func (s *Server) PostPullRequestClose(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string `json:"pull_request_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	pr, err := s.service.ClosePullRequest(r.Context(), req.PullRequestId)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.PullRequest{"pull_request": pr})
+}
+
+// PostPullRequestReopen открывает закрытый PR заново
+func (s *Server) PostPullRequestReopen(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string `json:"pull_request_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	pr, err := s.service.ReopenPullRequest(r.Context(), req.PullRequestId)
 	if err != nil {
-		handleError(w, err)
+		s.handleError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]*models.PullRequest{"pull_request": pr})
 }
 
-// PostPullRequestReassign переназначает ревьювера
+// PostPullRequestReview сохраняет вердикт ревьювера по PR
+func (s *Server) PostPullRequestReview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PullRequestId string               `json:"pull_request_id"`
+		ReviewerId    string               `json:"reviewer_id"`
+		Verdict       models.ReviewVerdict `json:"verdict"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	pr, err := s.service.ReviewPullRequest(r.Context(), req.PullRequestId, req.ReviewerId, req.Verdict)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]*models.PullRequest{"pull_request": pr})
+}
+
+// PostPullRequestReassign заменяет ревьювера на другого участника его команды
 func (s *Server) PostPullRequestReassign(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestId string `json:"pull_request_id"`
-		OldReviewerId string `json:"old_reviewer_id"`
-		NewReviewerId string `json:"new_reviewer_id"`
+		OldUserId     string `json:"old_user_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -121,23 +369,154 @@ func (s *Server) PostPullRequestReassign(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pr, err := s.service.ReassignReviewer(req.PullRequestId, req.OldReviewerId, req.NewReviewerId)
+	pr, replacedBy, err := s.service.ReassignReviewer(r.Context(), req.PullRequestId, req.OldUserId)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pr":          pr,
+		"replaced_by": replacedBy,
+	})
+}
+
+// GetPullRequestHistory возвращает журнал назначений и изменений статуса PR
+func (s *Server) GetPullRequestHistory(w http.ResponseWriter, r *http.Request, params GetPullRequestHistoryParams) {
+	events, err := s.service.GetPullRequestHistory(r.Context(), params.PullRequestId)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pull_request_id": params.PullRequestId,
+		"events":          events,
+	})
+}
+
+// GetPullRequestGet возвращает PR по идентификатору
+func (s *Server) GetPullRequestGet(w http.ResponseWriter, r *http.Request, params GetPullRequestGetParams) {
+	pr, err := s.service.GetPullRequest(r.Context(), params.PullRequestId)
 	if err != nil {
-		handleError(w, err)
+		s.handleError(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]*models.PullRequest{"pull_request": pr})
 }
 
-// GetUsersGetReview получает PR'ы, где пользователь назначен ревьювером
+// GetPullRequestList возвращает страницу PR, подходящих под фильтры
+func (s *Server) GetPullRequestList(w http.ResponseWriter, r *http.Request, params GetPullRequestListParams) {
+	query := service.PullRequestQuery{
+		AuthorId:      params.AuthorId,
+		TeamName:      params.TeamName,
+		ReviewerId:    params.ReviewerId,
+		Status:        (*models.PullRequestStatus)(params.Status),
+		NameContains:  params.Name,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+	}
+	if !s.setPage(w, r, &query, params.Sort, params.Cursor, params.Limit) {
+		return
+	}
+
+	prs, next, err := s.service.ListPullRequests(r.Context(), query)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	response := map[string]interface{}{"pull_requests": prs}
+	if next != "" {
+		response["next_cursor"] = next
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// setPage переносит порядок и страницу в query; при ошибке отвечает 400 и возвращает false
+func (s *Server) setPage(w http.ResponseWriter, r *http.Request, query *service.PullRequestQuery, sort *SortQuery, cursor *CursorQuery, limit *LimitQuery) bool {
+	if sort != nil {
+		switch *sort {
+		case CreatedAsc:
+		case CreatedDesc:
+			query.Descending = true
+		default:
+			writeError(w, http.StatusBadRequest, models.INVALIDREQUEST, "неизвестный порядок сортировки")
+			return false
+		}
+	}
+	if cursor != nil {
+		query.Cursor = *cursor
+	}
+	if limit != nil {
+		// 0 в PullRequestQuery означает размер по умолчанию, а явный limit=0 — ошибка
+		if *limit == 0 {
+			s.handleError(w, r, service.ErrInvalidLimit)
+			return false
+		}
+		query.Limit = *limit
+	}
+	return true
+}
+
+// GetUsersGetReview получает страницу PR'ов, где пользователь назначен ревьювером
 func (s *Server) GetUsersGetReview(w http.ResponseWriter, r *http.Request, params GetUsersGetReviewParams) {
-	prs := s.service.GetUserPullRequests(params.UserId)
+	query := service.PullRequestQuery{
+		Status:        (*models.PullRequestStatus)(params.Status),
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+	}
+	if !s.setPage(w, r, &query, params.Sort, params.Cursor, params.Limit) {
+		return
+	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
+	prs, next, err := s.service.GetUserPullRequests(r.Context(), params.UserId, query)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	response := map[string]interface{}{
 		"user_id":       params.UserId,
 		"pull_requests": prs,
-	})
+	}
+	if next != "" {
+		response["next_cursor"] = next
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetStatsReviewers возвращает статистику назначений по ревьюверам
+func (s *Server) GetStatsReviewers(w http.ResponseWriter, r *http.Request, params GetStatsReviewersParams) {
+	if s.opts.DisableStats {
+		writeDisabled(w)
+		return
+	}
+
+	stats, err := s.service.GetReviewerStats(r.Context(), params.From, params.To, params.TeamName)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]models.ReviewerStats{"reviewers": stats})
+}
+
+// GetStatsTeams возвращает статистику назначений по командам
+func (s *Server) GetStatsTeams(w http.ResponseWriter, r *http.Request, params GetStatsTeamsParams) {
+	if s.opts.DisableStats {
+		writeDisabled(w)
+		return
+	}
+
+	stats, err := s.service.GetTeamStats(r.Context(), params.From, params.To)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]models.TeamStats{"teams": stats})
 }
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
@@ -158,15 +537,206 @@ func writeError(w http.ResponseWriter, status int, code models.ErrorResponseErro
 	})
 }
 
-func handleError(w http.ResponseWriter, err error) {
+// ParamErrorHandler возвращает StdHTTPServerOptions.ErrorHandlerFunc, который отвечает 400 INVALID_REQUEST,
+// если обёртка не смогла разобрать параметры запроса. Обёртка вызывает его в обход своих middlewares,
+// поэтому их нужно передать сюда же, чтобы такие ответы попадали, например, в метрики.
+func ParamErrorHandler(middlewares ...MiddlewareFunc) func(w http.ResponseWriter, r *http.Request, err error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusBadRequest, models.INVALIDREQUEST, err.Error())
+		})
+		for _, middleware := range middlewares {
+			handler = middleware(handler)
+		}
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// PostIntegrationsGithubWebhook принимает вебхук GitHub pull_request
+func (s *Server) PostIntegrationsGithubWebhook(w http.ResponseWriter, r *http.Request, params PostIntegrationsGithubWebhookParams) {
+	if s.github == nil {
+		writeDisabled(w)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxIntegrationBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+	if params.XHubSignature256 == nil || !s.github.VerifySignature(body, *params.XHubSignature256) {
+		writeError(w, http.StatusUnauthorized, models.INVALIDSIGNATURE, "подпись вебхука отсутствует или не совпадает")
+		return
+	}
+
+	var event string
+	if params.XGitHubEvent != nil {
+		event = *params.XGitHubEvent
+	}
+
+	result, err := s.github.Handle(r.Context(), event, body)
+	if errors.Is(err, integrations.ErrInvalidPayload) {
+		writeError(w, http.StatusBadRequest, models.INVALIDREQUEST, err.Error())
+		return
+	}
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// PostIntegrationsGithubSetUser сопоставляет логин GitHub пользователю
+func (s *Server) PostIntegrationsGithubSetUser(w http.ResponseWriter, r *http.Request) {
+	if s.github == nil {
+		writeDisabled(w)
+		return
+	}
+
+	var req struct {
+		GithubLogin string `json:"github_login"`
+		UserId      string `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	if err := s.service.SetGitHubUser(r.Context(), req.GithubLogin, req.UserId); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, req)
+}
+
+// PostIntegrationsGitlabWebhook принимает вебхук GitLab Merge Request Hook
+func (s *Server) PostIntegrationsGitlabWebhook(w http.ResponseWriter, r *http.Request, params PostIntegrationsGitlabWebhookParams) {
+	if s.gitlab == nil {
+		writeDisabled(w)
+		return
+	}
+	if params.XGitlabToken == nil || !s.gitlab.VerifyToken(*params.XGitlabToken) {
+		writeError(w, http.StatusUnauthorized, models.INVALIDSIGNATURE, "токен вебхука отсутствует или не совпадает")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxIntegrationBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	var event string
+	if params.XGitlabEvent != nil {
+		event = *params.XGitlabEvent
+	}
+
+	result, err := s.gitlab.Handle(r.Context(), event, body)
+	if errors.Is(err, integrations.ErrInvalidPayload) {
+		writeError(w, http.StatusBadRequest, models.INVALIDREQUEST, err.Error())
+		return
+	}
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// PostWebhooksAdd подписывает URL на события PR и назначений
+func (s *Server) PostWebhooksAdd(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Url    string                    `json:"url"`
+		Secret string                    `json:"secret"`
+		Events []models.WebhookEventType `json:"events"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	sub, err := s.service.CreateWebhookSubscription(r.Context(), req.Url, req.Secret, req.Events)
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]*models.WebhookSubscription{"subscription": sub})
+}
+
+// GetWebhooksList возвращает подписки на вебхуки
+func (s *Server) GetWebhooksList(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.service.ListWebhookSubscriptions(r.Context())
+	if err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]models.WebhookSubscription{"subscriptions": subs})
+}
+
+// PostWebhooksDelete удаляет подписку
+func (s *Server) PostWebhooksDelete(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SubscriptionId string `json:"subscription_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, models.NOTFOUND, "неверное тело запроса")
+		return
+	}
+
+	if err := s.service.DeleteWebhookSubscription(r.Context(), req.SubscriptionId); err != nil {
+		s.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeDisabled отвечает на запрос к эндпоинту, выключенному в конфигурации
+func writeDisabled(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, models.NOTFOUND, "эндпоинт отключён в конфигурации")
+}
+
+// handleError отвечает клиенту кодом, соответствующим ошибке. Непредвиденные ошибки
+// и сбои хранилища пишутся в лог с цепочкой причин: клиент получает только общее сообщение.
+func (s *Server) handleError(w http.ResponseWriter, r *http.Request, err error) {
 	var serviceErr *service.ServiceError
 	if errors.As(err, &serviceErr) {
 		status := http.StatusBadRequest
-		if serviceErr.Code == models.NOTFOUND {
+		switch serviceErr.Code {
+		case models.NOTFOUND:
 			status = http.StatusNotFound
+		case models.PREXISTS, models.PRMERGED, models.PRCLOSED, models.PRDRAFT, models.NOTASSIGNED, models.NOTENOUGHAPPROVALS, models.NOCANDIDATE:
+			status = http.StatusConflict
 		}
 		writeError(w, status, serviceErr.Code, serviceErr.Message)
 		return
 	}
-	writeError(w, http.StatusInternalServerError, models.NOTFOUND, "внутренняя ошибка сервера")
+	// Клиент отключился, и отвечать уже некому: это не сбой сервера
+	if errors.Is(err, context.Canceled) || errors.Is(r.Context().Err(), context.Canceled) {
+		s.opts.Logger.LogAttrs(r.Context(), slog.LevelDebug, "Запрос отменён клиентом", logging.Err(err))
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	}
+	// Нарушение ограничения целостности — обычно гонка с параллельным запросом
+	if errors.Is(err, db.ErrConflict) {
+		s.opts.Logger.LogAttrs(r.Context(), slog.LevelWarn, "Конфликт с существующими данными", logging.Err(err))
+		writeError(w, http.StatusConflict, models.CONFLICT, "данные изменились параллельным запросом, повторите запрос")
+		return
+	}
+	// Сбой соединения с БД отличаем от прочих ошибок, чтобы клиент мог повторить запрос
+	if errors.Is(err, db.ErrUnavailable) {
+		s.opts.Logger.LogAttrs(r.Context(), slog.LevelWarn, "Хранилище недоступно", logging.Err(err))
+		writeError(w, http.StatusServiceUnavailable, models.UNAVAILABLE, "хранилище временно недоступно")
+		return
+	}
+	s.opts.Logger.LogAttrs(r.Context(), slog.LevelError, "Внутренняя ошибка", logging.Err(err))
+	writeError(w, http.StatusInternalServerError, models.INTERNAL, "внутренняя ошибка сервера")
 }
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"pr-reviewer/internal/webhook"
+	"strings"
+	"testing"
+)
+
+// brokenStorage имитирует потерю соединения с БД
+type brokenStorage struct {
+	*db.MemoryStorage
+}
+
+func (brokenStorage) Ping(context.Context) error {
+	return db.ErrUnavailable
+}
+
+func serveHealth(t *testing.T, storage db.Repository, path string) (int, models.HealthStatus) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	Handler(NewServer(service.NewService(storage, service.Options{}), Options{})).ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+	var status models.HealthStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	return rec.Code, status
+}
+
+func TestHealthReady(t *testing.T) {
+	code, status := serveHealth(t, db.NewMemoryStorage(), "/health/ready")
+
+	if code != http.StatusOK || status.Status != models.Ok {
+		t.Fatalf("Ожидался статус 200/ok, получен %d/%s", code, status.Status)
+	}
+	if status.MigrationVersion == nil {
+		t.Fatal("В ответе должна быть версия миграций")
+	}
+}
+
+func TestHealthReadyStorageUnavailable(t *testing.T) {
+	storage := brokenStorage{db.NewMemoryStorage()}
+
+	code, status := serveHealth(t, storage, "/health/ready")
+	if code != http.StatusServiceUnavailable || status.Status != models.Unavailable {
+		t.Fatalf("Ожидался статус 503/unavailable, получен %d/%s", code, status.Status)
+	}
+
+	// Liveness не зависит от БД
+	code, status = serveHealth(t, storage, "/health/live")
+	if code != http.StatusOK || status.Status != models.Ok {
+		t.Fatalf("Ожидался статус 200/ok, получен %d/%s", code, status.Status)
+	}
+}
+
+func TestHandleErrorStorageCategories(t *testing.T) {
+	s := NewServer(service.NewService(db.NewMemoryStorage(), service.Options{}), Options{})
+
+	tests := []struct {
+		err    error
+		status int
+		code   models.ErrorResponseErrorCode
+	}{
+		{fmt.Errorf("ошибка создания PR: %w", db.ErrConflict), http.StatusConflict, models.CONFLICT},
+		{fmt.Errorf("ошибка при получении PR: %w", db.ErrUnavailable), http.StatusServiceUnavailable, models.UNAVAILABLE},
+		{errors.New("неизвестная ошибка"), http.StatusInternalServerError, models.INTERNAL},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.handleError(rec, httptest.NewRequest("GET", "/", nil), tt.err)
+
+		var resp models.ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Ошибка декодирования ответа: %v", err)
+		}
+		if rec.Code != tt.status || resp.Error.Code != tt.code {
+			t.Fatalf("%v: ожидался ответ %d %s, получен %d %s", tt.err, tt.status, tt.code, rec.Code, resp.Error.Code)
+		}
+	}
+
+	// Отключение клиента не считается внутренней ошибкой
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+	s.handleError(rec, httptest.NewRequest("GET", "/", nil).WithContext(ctx), fmt.Errorf("ошибка при получении PR: %w", ctx.Err()))
+	if rec.Code != statusClientClosedRequest {
+		t.Fatalf("Ожидался статус %d, получен %d", statusClientClosedRequest, rec.Code)
+	}
+}
+
+func TestDisabledStats(t *testing.T) {
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+	handler := Handler(NewServer(svc, Options{DisableStats: true}))
+
+	for _, path := range []string{"/stats/reviewers", "/stats/teams"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: ожидался статус 404, получен %d", path, rec.Code)
+		}
+	}
+}
+
+func TestMalformedQueryParams(t *testing.T) {
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+	handler := HandlerWithOptions(NewServer(svc, Options{}), StdHTTPServerOptions{ErrorHandlerFunc: ParamErrorHandler()})
+
+	for _, path := range []string{
+		"/users/getReview?user_id=u1&limit=abc",
+		"/users/getReview?user_id=u1&created_after=yesterday",
+		"/pullRequest/list?limit=abc",
+		"/pullRequest/list?created_after=yesterday",
+		"/pullRequest/list?created_before=2026-13-01",
+		"/pullRequest/list?status=DRAFT",
+		"/stats/reviewers?from=yesterday",
+		"/stats/teams?to=2026-13-01",
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+		var resp models.ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: ошибка декодирования ответа: %v", path, err)
+		}
+		if rec.Code != http.StatusBadRequest || resp.Error.Code != models.INVALIDREQUEST {
+			t.Fatalf("%s: ожидался ответ 400 INVALID_REQUEST, получен %d %s", path, rec.Code, resp.Error.Code)
+		}
+	}
+}
+
+func TestGitHubWebhookSignature(t *testing.T) {
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+	body := `{"zen": "Design for failure."}`
+
+	serve := func(opts Options, signature string) int {
+		req := httptest.NewRequest("POST", "/integrations/github/webhook", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "ping")
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature-256", signature)
+		}
+		rec := httptest.NewRecorder()
+		Handler(NewServer(svc, opts)).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	opts := Options{GitHubWebhookSecret: "s3cr3t"}
+	if code := serve(opts, webhook.Sign("s3cr3t", []byte(body))); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := serve(opts, webhook.Sign("other", []byte(body))); code != http.StatusUnauthorized {
+		t.Fatalf("Ожидался статус 401, получен %d", code)
+	}
+	if code := serve(opts, ""); code != http.StatusUnauthorized {
+		t.Fatalf("Без подписи ожидался статус 401, получен %d", code)
+	}
+	// Без секрета интеграция отключена
+	if code := serve(Options{}, webhook.Sign("", []byte(body))); code != http.StatusNotFound {
+		t.Fatalf("Ожидался статус 404, получен %d", code)
+	}
+}
+
+func TestGitHubWebhookInvalidPayload(t *testing.T) {
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+	body := `{"action": "opened"}`
+
+	req := httptest.NewRequest("POST", "/integrations/github/webhook", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", webhook.Sign("s3cr3t", []byte(body)))
+	rec := httptest.NewRecorder()
+	Handler(NewServer(svc, Options{GitHubWebhookSecret: "s3cr3t"})).ServeHTTP(rec, req)
+
+	var resp models.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || resp.Error.Code != models.INVALIDREQUEST {
+		t.Fatalf("Ожидался ответ 400 INVALID_REQUEST, получен %d %s", rec.Code, resp.Error.Code)
+	}
+}
+
+func TestGitLabWebhookToken(t *testing.T) {
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+
+	serve := func(opts Options, token string) int {
+		req := httptest.NewRequest("POST", "/integrations/gitlab/webhook", strings.NewReader(`{"object_kind": "push"}`))
+		req.Header.Set("X-Gitlab-Event", "Push Hook")
+		if token != "" {
+			req.Header.Set("X-Gitlab-Token", token)
+		}
+		rec := httptest.NewRecorder()
+		Handler(NewServer(svc, opts)).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	opts := Options{GitLabWebhookToken: "t0ken"}
+	if code := serve(opts, "t0ken"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получен %d", code)
+	}
+	if code := serve(opts, "other"); code != http.StatusUnauthorized {
+		t.Fatalf("Ожидался статус 401, получен %d", code)
+	}
+	if code := serve(opts, ""); code != http.StatusUnauthorized {
+		t.Fatalf("Без токена ожидался статус 401, получен %d", code)
+	}
+	// Без токена в конфигурации интеграция отключена
+	if code := serve(Options{}, "t0ken"); code != http.StatusNotFound {
+		t.Fatalf("Ожидался статус 404, получен %d", code)
+	}
+}
+
+func TestGitLabWebhookInvalidPayload(t *testing.T) {
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+
+	req := httptest.NewRequest("POST", "/integrations/gitlab/webhook", strings.NewReader(`{"object_kind": "merge_request"}`))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", "t0ken")
+	rec := httptest.NewRecorder()
+	Handler(NewServer(svc, Options{GitLabWebhookToken: "t0ken"})).ServeHTTP(rec, req)
+
+	var resp models.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Ошибка декодирования ответа: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || resp.Error.Code != models.INVALIDREQUEST {
+		t.Fatalf("Ожидался ответ 400 INVALID_REQUEST, получен %d %s", rec.Code, resp.Error.Code)
+	}
+}
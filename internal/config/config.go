@@ -0,0 +1,416 @@
+// Package config собирает конфигурацию сервиса из значений по умолчанию,
+// YAML-файла, переменных окружения и флагов командной строки (в порядке возрастания приоритета).
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config — эффективная конфигурация сервиса
+type Config struct {
+	HTTP         HTTPConfig         `yaml:"http"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Reviewers    ReviewersConfig    `yaml:"reviewers"`
+	Log          LogConfig          `yaml:"log"`
+	Webhooks     WebhooksConfig     `yaml:"webhooks"`
+	Integrations IntegrationsConfig `yaml:"integrations"`
+	Features     FeaturesConfig     `yaml:"features"`
+}
+
+// HTTPConfig — параметры HTTP-сервера
+type HTTPConfig struct {
+	Port string `yaml:"port"`
+	// RequestTimeout ограничивает обработку одного запроса; 0 — без ограничения
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// ReadHeaderTimeout ограничивает чтение заголовков, чтобы медленные клиенты не удерживали соединения
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	ReadinessTimeout  time.Duration `yaml:"readiness_timeout"`
+}
+
+// DatabaseConfig — выбор хранилища и параметры пула соединений
+type DatabaseConfig struct {
+	// Storage — postgres или memory
+	Storage string `yaml:"storage"`
+	URL     string `yaml:"url"`
+	// Нулевые значения оставляют настройки database/sql по умолчанию
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+}
+
+// ReviewersConfig — параметры назначения ревьюверов
+type ReviewersConfig struct {
+	// DefaultCount — сколько ревьюверов назначается на новый PR
+	DefaultCount int `yaml:"default_count"`
+}
+
+// LogConfig — параметры логирования
+type LogConfig struct {
+	// Level — debug, info, warn или error
+	Level string `yaml:"level"`
+	// Format — json или text
+	Format string `yaml:"format"`
+}
+
+// WebhooksConfig — параметры доставки исходящих вебхуков
+type WebhooksConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// Timeout ограничивает один запрос к получателю
+	Timeout     time.Duration `yaml:"timeout"`
+	MaxAttempts int           `yaml:"max_attempts"`
+	// Задержка между попытками удваивается от InitialBackoff до MaxBackoff
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+}
+
+// IntegrationsConfig — приём вебхуков внешних систем
+type IntegrationsConfig struct {
+	GitHub GitHubConfig `yaml:"github"`
+	GitLab GitLabConfig `yaml:"gitlab"`
+}
+
+// GitHubConfig — параметры /integrations/github/*
+type GitHubConfig struct {
+	// WebhookSecret — секрет вебхука GitHub; пустая строка отключает интеграцию
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+// GitLabConfig — параметры /integrations/gitlab/webhook
+type GitLabConfig struct {
+	// WebhookToken — секретный токен вебхука GitLab; пустая строка отключает интеграцию
+	WebhookToken string `yaml:"webhook_token"`
+	// APIURL и APIToken включают запись назначенных ревьюверов в MR; задаются вместе
+	APIURL   string `yaml:"api_url"`
+	APIToken string `yaml:"api_token"`
+}
+
+// FeaturesConfig включает и выключает необязательные группы эндпоинтов
+type FeaturesConfig struct {
+	Stats            bool `yaml:"stats"`
+	TeamDeactivation bool `yaml:"team_deactivation"`
+	// Metrics включает /metrics в формате Prometheus
+	Metrics bool `yaml:"metrics"`
+}
+
+// Default возвращает конфигурацию по умолчанию
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Port:              "8080",
+			RequestTimeout:    5 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      10 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+			ReadinessTimeout:  2 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Storage:         "postgres",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Reviewers: ReviewersConfig{DefaultCount: 2},
+		Log:       LogConfig{Level: "info", Format: "json"},
+		Webhooks: WebhooksConfig{
+			PollInterval:   time.Second,
+			Timeout:        5 * time.Second,
+			MaxAttempts:    8,
+			InitialBackoff: time.Second,
+			MaxBackoff:     10 * time.Minute,
+		},
+		Features: FeaturesConfig{Stats: true, TeamDeactivation: true, Metrics: true},
+	}
+}
+
+// option связывает поле конфигурации с переменной окружения и флагом.
+// ptr — указатель на *string, *int, *bool или *time.Duration.
+type option struct {
+	env   string
+	flag  string
+	usage string
+	ptr   any
+}
+
+func (c *Config) options() []option {
+	return []option{
+		{"PORT", "port", "порт HTTP-сервера", &c.HTTP.Port},
+		{"REQUEST_TIMEOUT", "request-timeout", "таймаут обработки запроса (0 — без ограничения)", &c.HTTP.RequestTimeout},
+		{"READ_HEADER_TIMEOUT", "read-header-timeout", "таймаут чтения заголовков запроса", &c.HTTP.ReadHeaderTimeout},
+		{"READ_TIMEOUT", "read-timeout", "таймаут чтения запроса", &c.HTTP.ReadTimeout},
+		{"WRITE_TIMEOUT", "write-timeout", "таймаут записи ответа", &c.HTTP.WriteTimeout},
+		{"IDLE_TIMEOUT", "idle-timeout", "время жизни простаивающего keep-alive соединения", &c.HTTP.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "сколько ждать завершения запросов при остановке", &c.HTTP.ShutdownTimeout},
+		{"READINESS_TIMEOUT", "readiness-timeout", "таймаут проверки БД в /health/ready", &c.HTTP.ReadinessTimeout},
+		{"STORAGE", "storage", "хранилище: postgres или memory (данные не сохраняются между запусками)", &c.Database.Storage},
+		{"DATABASE_URL", "database-url", "строка подключения к PostgreSQL", &c.Database.URL},
+		{"DB_MAX_OPEN_CONNS", "db-max-open-conns", "максимум открытых соединений с БД (0 — без ограничения)", &c.Database.MaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", "db-max-idle-conns", "максимум простаивающих соединений с БД", &c.Database.MaxIdleConns},
+		{"DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "максимальное время жизни соединения с БД", &c.Database.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", "db-conn-max-idle-time", "максимальное время простоя соединения с БД", &c.Database.ConnMaxIdleTime},
+		{"DEFAULT_REVIEWERS", "default-reviewers", "число ревьюверов на новый PR", &c.Reviewers.DefaultCount},
+		{"LOG_LEVEL", "log-level", "уровень логирования: debug, info, warn, error", &c.Log.Level},
+		{"LOG_FORMAT", "log-format", "формат логов: json или text", &c.Log.Format},
+		{"WEBHOOK_POLL_INTERVAL", "webhook-poll-interval", "как часто проверять очередь вебхуков", &c.Webhooks.PollInterval},
+		{"WEBHOOK_TIMEOUT", "webhook-timeout", "таймаут запроса к получателю вебхука", &c.Webhooks.Timeout},
+		{"WEBHOOK_MAX_ATTEMPTS", "webhook-max-attempts", "число попыток доставки вебхука", &c.Webhooks.MaxAttempts},
+		{"WEBHOOK_INITIAL_BACKOFF", "webhook-initial-backoff", "задержка перед повтором доставки вебхука", &c.Webhooks.InitialBackoff},
+		{"WEBHOOK_MAX_BACKOFF", "webhook-max-backoff", "максимальная задержка между попытками доставки вебхука", &c.Webhooks.MaxBackoff},
+		{"GITHUB_WEBHOOK_SECRET", "github-webhook-secret", "секрет вебхука GitHub (пусто — интеграция отключена)", &c.Integrations.GitHub.WebhookSecret},
+		{"GITLAB_WEBHOOK_TOKEN", "gitlab-webhook-token", "токен вебхука GitLab (пусто — интеграция отключена)", &c.Integrations.GitLab.WebhookToken},
+		{"GITLAB_API_URL", "gitlab-api-url", "адрес GitLab для записи ревьюверов в MR (пусто — запись отключена)", &c.Integrations.GitLab.APIURL},
+		{"GITLAB_API_TOKEN", "gitlab-api-token", "токен доступа к API GitLab с правом api", &c.Integrations.GitLab.APIToken},
+		{"FEATURE_STATS", "feature-stats", "включить эндпоинты /stats/*", &c.Features.Stats},
+		{"FEATURE_TEAM_DEACTIVATION", "feature-team-deactivation", "включить /team/deactivateUsers", &c.Features.TeamDeactivation},
+		{"FEATURE_METRICS", "feature-metrics", "включить /metrics", &c.Features.Metrics},
+	}
+}
+
+// Load собирает конфигурацию: значения по умолчанию, затем YAML-файл (флаг -config
+// или переменная CONFIG_FILE), затем переменные окружения и, наконец, флаги из args.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+	opts := cfg.options()
+
+	fs := flag.NewFlagSet("pr-reviewer", flag.ContinueOnError)
+	configFile := fs.String("config", getenv("CONFIG_FILE"), "путь к YAML-файлу конфигурации")
+
+	// Флаги применяются после файла и окружения, поэтому пока только запоминаем значения
+	type flagValue struct {
+		opt option
+		raw string
+	}
+	var flagValues []flagValue
+	for _, opt := range opts {
+		record := func(raw string) error {
+			flagValues = append(flagValues, flagValue{opt: opt, raw: raw})
+			return nil
+		}
+		if _, ok := opt.ptr.(*bool); ok {
+			fs.BoolFunc(opt.flag, opt.usage, record)
+		} else {
+			fs.Func(opt.flag, opt.usage, record)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("ошибка чтения файла конфигурации: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("ошибка разбора файла конфигурации %s: %w", *configFile, err)
+		}
+	}
+
+	for _, opt := range opts {
+		raw := getenv(opt.env)
+		if raw == "" {
+			continue
+		}
+		if err := setValue(opt.ptr, raw); err != nil {
+			return Config{}, fmt.Errorf("некорректный %s %q: %w", opt.env, raw, err)
+		}
+	}
+
+	for _, v := range flagValues {
+		if err := setValue(v.opt.ptr, v.raw); err != nil {
+			return Config{}, fmt.Errorf("некорректный флаг -%s %q: %w", v.opt.flag, v.raw, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func setValue(ptr any, raw string) error {
+	switch p := ptr.(type) {
+	case *string:
+		*p = raw
+	case *int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		*p = v
+	case *bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		*p = v
+	case *time.Duration:
+		v, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		*p = v
+	default:
+		return fmt.Errorf("неподдерживаемый тип %T", ptr)
+	}
+	return nil
+}
+
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу
+func (c Config) Validate() error {
+	var errs []error
+
+	if port, err := strconv.Atoi(c.HTTP.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("некорректный порт %q", c.HTTP.Port))
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"http.request_timeout", c.HTTP.RequestTimeout},
+		{"http.read_header_timeout", c.HTTP.ReadHeaderTimeout},
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"database.conn_max_lifetime", c.Database.ConnMaxLifetime},
+		{"database.conn_max_idle_time", c.Database.ConnMaxIdleTime},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			errs = append(errs, fmt.Errorf("%s не может быть отрицательным", d.name))
+		}
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("http.shutdown_timeout должен быть положительным"))
+	}
+	if c.HTTP.ReadinessTimeout <= 0 {
+		errs = append(errs, errors.New("http.readiness_timeout должен быть положительным"))
+	}
+
+	switch c.Database.Storage {
+	case "postgres":
+		if c.Database.URL == "" {
+			errs = append(errs, errors.New("пустой DATABASE_URL"))
+		}
+	case "memory":
+	default:
+		errs = append(errs, fmt.Errorf("неизвестное хранилище %q", c.Database.Storage))
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("размеры пула соединений не могут быть отрицательными"))
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns не может превышать database.max_open_conns"))
+	}
+
+	if c.Reviewers.DefaultCount < 1 {
+		errs = append(errs, errors.New("reviewers.default_count должен быть не меньше 1"))
+	}
+
+	webhookDurations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"webhooks.poll_interval", c.Webhooks.PollInterval},
+		{"webhooks.timeout", c.Webhooks.Timeout},
+		{"webhooks.initial_backoff", c.Webhooks.InitialBackoff},
+	}
+	for _, d := range webhookDurations {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s должен быть положительным", d.name))
+		}
+	}
+	if c.Webhooks.MaxBackoff < c.Webhooks.InitialBackoff {
+		errs = append(errs, errors.New("webhooks.max_backoff не может быть меньше webhooks.initial_backoff"))
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, errors.New("webhooks.max_attempts должен быть не меньше 1"))
+	}
+
+	gitlab := c.Integrations.GitLab
+	if (gitlab.APIURL == "") != (gitlab.APIToken == "") {
+		errs = append(errs, errors.New("integrations.gitlab.api_url и integrations.gitlab.api_token задаются вместе"))
+	}
+	if gitlab.APIURL != "" {
+		if u, err := url.Parse(gitlab.APIURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("integrations.gitlab.api_url должен быть абсолютным http(s) URL: %q", gitlab.APIURL))
+		}
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("неизвестный уровень логирования %q", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("неизвестный формат логов %q", c.Log.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// sensitiveDSNParams — параметры строки подключения libpq, значения которых скрываются в логе
+var sensitiveDSNParams = []string{"password", "sslpassword"}
+
+// Redacted возвращает копию конфигурации, в которой секреты и пароль в строке подключения скрыты
+func (c Config) Redacted() Config {
+	if c.Integrations.GitHub.WebhookSecret != "" {
+		c.Integrations.GitHub.WebhookSecret = "xxxxx"
+	}
+	if c.Integrations.GitLab.WebhookToken != "" {
+		c.Integrations.GitLab.WebhookToken = "xxxxx"
+	}
+	if c.Integrations.GitLab.APIToken != "" {
+		c.Integrations.GitLab.APIToken = "xxxxx"
+	}
+	if c.Database.URL == "" {
+		return c
+	}
+	if u, err := url.Parse(c.Database.URL); err == nil && u.Scheme != "" {
+		// Пароль можно передать и в параметрах: postgres://u@h/db?password=...
+		query := u.Query()
+		masked := false
+		for key := range query {
+			if slices.Contains(sensitiveDSNParams, strings.ToLower(key)) {
+				query.Set(key, "xxxxx")
+				masked = true
+			}
+		}
+		if masked {
+			u.RawQuery = query.Encode()
+		}
+		c.Database.URL = u.Redacted()
+	} else {
+		// Строку в формате "key=value" не разбираем, а скрываем целиком
+		c.Database.URL = "xxxxx"
+	}
+	return c
+}
+
+// String возвращает конфигурацию в YAML со скрытыми секретами, для вывода в лог
+func (c Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("<ошибка форматирования конфигурации: %v>", err)
+	}
+	return strings.TrimRight(string(data), "\n")
+}
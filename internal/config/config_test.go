@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func envFrom(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(nil, envFrom(map[string]string{"DATABASE_URL": "postgres://db/app"}))
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+
+	want := Default()
+	want.Database.URL = "postgres://db/app"
+	if cfg != want {
+		t.Fatalf("Ожидалась конфигурация по умолчанию, получено %+v", cfg)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	file := `
+http:
+  port: "9000"
+  request_timeout: 3s
+  shutdown_timeout: 30s
+database:
+  storage: memory
+reviewers:
+  default_count: 3
+log:
+  level: debug
+`
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := envFrom(map[string]string{
+		"CONFIG_FILE":     path,
+		"PORT":            "9100",
+		"REQUEST_TIMEOUT": "4s",
+	})
+	cfg, err := Load([]string{"-port", "9200", "-feature-stats=false"}, env)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+
+	// Флаг важнее окружения, окружение важнее файла, файл важнее значений по умолчанию
+	if cfg.HTTP.Port != "9200" {
+		t.Fatalf("Ожидался порт из флага, получен %s", cfg.HTTP.Port)
+	}
+	if cfg.HTTP.RequestTimeout != 4*time.Second {
+		t.Fatalf("Ожидался таймаут из окружения, получен %s", cfg.HTTP.RequestTimeout)
+	}
+	if cfg.HTTP.ShutdownTimeout != 30*time.Second || cfg.Reviewers.DefaultCount != 3 || cfg.Log.Level != "debug" {
+		t.Fatalf("Значения из файла не применены: %+v", cfg)
+	}
+	if cfg.HTTP.IdleTimeout != Default().HTTP.IdleTimeout {
+		t.Fatalf("Незаданное значение должно остаться по умолчанию, получено %s", cfg.HTTP.IdleTimeout)
+	}
+	if cfg.Features.Stats || !cfg.Features.TeamDeactivation {
+		t.Fatalf("Неверные переключатели: %+v", cfg.Features)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	env := envFrom(map[string]string{
+		"PORT":                "0",
+		"DEFAULT_REVIEWERS":   "0",
+		"LOG_LEVEL":           "verbose",
+		"WEBHOOK_MAX_BACKOFF": "1ms",
+		"GITLAB_API_URL":      "gitlab.example.com",
+		"READ_HEADER_TIMEOUT": "-1s",
+	})
+
+	_, err := Load(nil, env)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка валидации")
+	}
+	for _, part := range []string{"порт", "DATABASE_URL", "default_count", "verbose", "webhooks.max_backoff", "api_token задаются вместе", "api_url должен быть", "http.read_header_timeout"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Fatalf("Ошибка должна упоминать %q: %v", part, err)
+		}
+	}
+
+	if _, err := Load(nil, envFrom(map[string]string{"STORAGE": "memory", "REQUEST_TIMEOUT": "soon"})); err == nil {
+		t.Fatal("Ожидалась ошибка разбора длительности")
+	}
+}
+
+func TestStringMasksSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Database.URL = "postgres://postgres:hunter2@db:5432/avitotech?sslmode=disable"
+	cfg.Integrations.GitHub.WebhookSecret = "gh-hook-key"
+	cfg.Integrations.GitLab.WebhookToken = "gl-hook-key"
+	cfg.Integrations.GitLab.APIToken = "glpat-key"
+
+	out := cfg.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "hook-key") || strings.Contains(out, "glpat-key") {
+		t.Fatalf("Секрет попал в вывод конфигурации:\n%s", out)
+	}
+	if !strings.Contains(out, "postgres:xxxxx@db:5432") || !strings.Contains(out, "request_timeout: 5s") {
+		t.Fatalf("Неожиданный вывод конфигурации:\n%s", out)
+	}
+
+	cfg.Database.URL = "postgres://postgres@db:5432/avitotech?password=hunter2&sslpassword=hunter3&sslmode=require"
+	out = cfg.String()
+	if strings.Contains(out, "hunter") || !strings.Contains(out, "sslmode=require") {
+		t.Fatalf("Пароль в параметрах строки подключения должен быть скрыт:\n%s", out)
+	}
+
+	cfg.Database.URL = "host=db user=postgres password=hunter2"
+	if strings.Contains(cfg.String(), "hunter2") {
+		t.Fatal("Пароль в формате key=value попал в вывод конфигурации")
+	}
+}
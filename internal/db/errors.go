@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Категории ошибок хранилища. Реализации Repository добавляют их в цепочку ошибок,
+// чтобы вызывающий мог проверить их через errors.Is.
+var (
+	// ErrNotFound — запрошенная запись не существует
+	ErrNotFound = errors.New("запись не найдена")
+	// ErrConflict — операция нарушает ограничение целостности (уникальность, внешний ключ)
+	ErrConflict = errors.New("конфликт с существующими данными")
+	// ErrUnavailable — БД недоступна или не ответила вовремя
+	ErrUnavailable = errors.New("БД недоступна")
+)
+
+// classify добавляет к ошибке драйвера подходящую категорию.
+// Ошибки, не попавшие ни в одну категорию, возвращаются как есть.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var category error
+	var pgErr *pgconn.PgError
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrConflict), errors.Is(err, ErrUnavailable):
+		return err
+	case errors.Is(err, sql.ErrNoRows):
+		category = ErrNotFound
+	case errors.As(err, &pgErr):
+		switch {
+		case pgErr.Code == "23505", pgErr.Code == "23503":
+			category = ErrConflict
+		// 08 — ошибки соединения, 57P — остановка сервера, 53300 — исчерпан лимит соединений
+		case strings.HasPrefix(pgErr.Code, "08"), strings.HasPrefix(pgErr.Code, "57P"), pgErr.Code == "53300":
+			category = ErrUnavailable
+		}
+	case errors.As(err, &connectErr),
+		errors.As(err, &netErr),
+		errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, context.DeadlineExceeded):
+		category = ErrUnavailable
+	}
+
+	if category == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", category, err)
+}
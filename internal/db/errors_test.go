@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"нет строк", sql.ErrNoRows, ErrNotFound},
+		{"уникальность", &pgconn.PgError{Code: "23505"}, ErrConflict},
+		{"внешний ключ", fmt.Errorf("обёртка: %w", &pgconn.PgError{Code: "23503"}), ErrConflict},
+		{"обрыв соединения", &pgconn.PgError{Code: "08006"}, ErrUnavailable},
+		{"остановка сервера", &pgconn.PgError{Code: "57P01"}, ErrUnavailable},
+		{"таймаут", context.DeadlineExceeded, ErrUnavailable},
+		{"закрытое соединение", sql.ErrConnDone, ErrUnavailable},
+	}
+
+	for _, c := range cases {
+		got := classify(c.err)
+		if !errors.Is(got, c.want) {
+			t.Fatalf("%s: ожидалась категория %v, получено %v", c.name, c.want, got)
+		}
+		if !errors.Is(got, c.err) {
+			t.Fatalf("%s: исходная ошибка потеряна: %v", c.name, got)
+		}
+	}
+
+	// Синтаксическая ошибка SQL не относится ни к одной категории
+	syntaxErr := &pgconn.PgError{Code: "42601"}
+	got := classify(syntaxErr)
+	if errors.Is(got, ErrNotFound) || errors.Is(got, ErrConflict) || errors.Is(got, ErrUnavailable) {
+		t.Fatalf("Ошибка синтаксиса не должна получать категорию: %v", got)
+	}
+}
@@ -0,0 +1,759 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"pr-reviewer/internal/models"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage — потокобезопасное хранилище в памяти с той же семантикой, что у Storage.
+// Используется в тестах и для демонстрационного запуска без PostgreSQL.
+type MemoryStorage struct {
+	mu   *sync.Mutex
+	data *memoryData
+	// inTx означает, что mu уже захвачен вызовом InTx
+	inTx bool
+}
+
+type memoryData struct {
+	teams         map[string]*memoryTeam
+	users         map[string]models.User
+	pullRequests  map[string]models.PullRequest
+	reassignments []memoryReassignment
+	events        []models.PullRequestEvent
+	subscriptions map[string]memorySubscription
+	githubUsers   map[string]string
+	deliveries    []memoryDelivery
+	// lastDeliveryId не уменьшается при удалении подписок, чтобы id доставок не повторялись
+	lastDeliveryId int64
+}
+
+type memorySubscription struct {
+	sub    models.WebhookSubscription
+	secret string
+}
+
+type memoryDelivery struct {
+	WebhookDelivery
+	nextAttemptAt time.Time
+	lastError     string
+	done          bool
+}
+
+type memoryTeam struct {
+	reviewersRequired *int
+	reviewerStrategy  models.ReviewerStrategy
+	approvalsRequired int
+	roundRobinCursor  int64
+}
+
+type memoryReassignment struct {
+	pullRequestId string
+	oldUserId     string
+	newUserId     string
+	reassignedAt  time.Time
+}
+
+// NewMemoryStorage создаёт пустое хранилище в памяти
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		mu: &sync.Mutex{},
+		data: &memoryData{
+			teams:         map[string]*memoryTeam{},
+			users:         map[string]models.User{},
+			pullRequests:  map[string]models.PullRequest{},
+			subscriptions: map[string]memorySubscription{},
+			githubUsers:   map[string]string{},
+		},
+	}
+}
+
+// InTx выполняет fn под общей блокировкой хранилища.
+// Если fn вернула ошибку, данные восстанавливаются из снимка, сделанного до вызова.
+func (m *MemoryStorage) InTx(ctx context.Context, fn func(tx Repository) error) error {
+	if m.inTx {
+		return fn(m)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	snapshot := m.data.clone()
+	if err := fn(&MemoryStorage{mu: m.mu, data: m.data, inTx: true}); err != nil {
+		*m.data = *snapshot
+		return err
+	}
+	return nil
+}
+
+// lock захватывает блокировку, если она ещё не удерживается транзакцией, и возвращает функцию освобождения
+func (m *MemoryStorage) lock() func() {
+	if m.inTx {
+		return func() {}
+	}
+	m.mu.Lock()
+	return m.mu.Unlock
+}
+
+func (d *memoryData) clone() *memoryData {
+	c := &memoryData{
+		teams:          make(map[string]*memoryTeam, len(d.teams)),
+		users:          make(map[string]models.User, len(d.users)),
+		pullRequests:   make(map[string]models.PullRequest, len(d.pullRequests)),
+		reassignments:  slices.Clone(d.reassignments),
+		events:         slices.Clone(d.events),
+		subscriptions:  maps.Clone(d.subscriptions),
+		githubUsers:    maps.Clone(d.githubUsers),
+		deliveries:     slices.Clone(d.deliveries),
+		lastDeliveryId: d.lastDeliveryId,
+	}
+	for name, team := range d.teams {
+		teamCopy := *team
+		c.teams[name] = &teamCopy
+	}
+	for id, user := range d.users {
+		c.users[id] = user
+	}
+	for id, pr := range d.pullRequests {
+		c.pullRequests[id] = copyPullRequest(pr)
+	}
+	return c
+}
+
+// copyPullRequest копирует PR вместе со списками ревьюверов и вердиктов, чтобы вызывающий не менял хранимые данные
+func copyPullRequest(pr models.PullRequest) models.PullRequest {
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	pr.Reviews = slices.Clone(pr.Reviews)
+	return pr
+}
+
+// assignedReviews оставляет вердикты только назначенных ревьюверов, как удаление строк pr_reviewers
+func assignedReviews(reviews []models.Review, reviewers []string) []models.Review {
+	return slices.DeleteFunc(slices.Clone(reviews), func(r models.Review) bool {
+		return !slices.Contains(reviewers, r.ReviewerId)
+	})
+}
+
+// ---------- Team ----------
+
+func (m *MemoryStorage) TeamExists(_ context.Context, name string) (bool, error) {
+	defer m.lock()()
+
+	_, ok := m.data.teams[name]
+	return ok, nil
+}
+
+func (m *MemoryStorage) SaveTeam(_ context.Context, team *models.Team) error {
+	defer m.lock()()
+
+	// Как и в PostgreSQL, удалить участника, который автор PR, не даёт внешний ключ
+	for id, user := range m.data.users {
+		if user.TeamName != team.TeamName {
+			continue
+		}
+		for _, pr := range m.data.pullRequests {
+			if pr.AuthorId == id {
+				return fmt.Errorf("ошибка удаления предыдущих участников: пользователь %s является автором PR %s: %w", id, pr.PullRequestId, ErrConflict)
+			}
+		}
+	}
+
+	if _, ok := m.data.teams[team.TeamName]; !ok {
+		m.data.teams[team.TeamName] = &memoryTeam{}
+	}
+	if team.ReviewersRequired != nil {
+		count := *team.ReviewersRequired
+		m.data.teams[team.TeamName].reviewersRequired = &count
+	}
+
+	// Удалим старых участников, чтобы пересоздать; их назначения удаляются каскадно
+	for id, user := range m.data.users {
+		if user.TeamName == team.TeamName {
+			delete(m.data.users, id)
+			m.removeReviewer(id)
+		}
+	}
+
+	for _, member := range team.Members {
+		m.data.users[member.UserId] = models.User{
+			UserId:   member.UserId,
+			Username: member.Username,
+			TeamName: team.TeamName,
+			IsActive: member.IsActive,
+		}
+	}
+	return nil
+}
+
+// removeReviewer снимает пользователя со всех PR, как ON DELETE CASCADE в pr_reviewers
+func (m *MemoryStorage) removeReviewer(userId string) {
+	for id, pr := range m.data.pullRequests {
+		if slices.Contains(pr.AssignedReviewers, userId) {
+			pr.AssignedReviewers = slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), func(r string) bool {
+				return r == userId
+			})
+			pr.Reviews = assignedReviews(pr.Reviews, pr.AssignedReviewers)
+			m.data.pullRequests[id] = pr
+		}
+	}
+}
+
+func (m *MemoryStorage) GetTeam(_ context.Context, name string) (*models.Team, error) {
+	defer m.lock()()
+
+	stored, ok := m.data.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("команда %s: %w", name, ErrNotFound)
+	}
+
+	var members []models.TeamMember
+	for _, user := range m.data.users {
+		if user.TeamName == name {
+			members = append(members, models.TeamMember{
+				UserId:   user.UserId,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserId < members[j].UserId
+	})
+
+	team := &models.Team{
+		TeamName: name,
+		Members:  members,
+	}
+	if stored.reviewersRequired != nil {
+		count := *stored.reviewersRequired
+		team.ReviewersRequired = &count
+	}
+	return team, nil
+}
+
+func (m *MemoryStorage) SetTeamReviewersRequired(_ context.Context, name string, count int) error {
+	defer m.lock()()
+
+	team, ok := m.data.teams[name]
+	if !ok {
+		return fmt.Errorf("команда %s: %w", name, ErrNotFound)
+	}
+	team.reviewersRequired = &count
+	return nil
+}
+
+func (m *MemoryStorage) GetTeamSettings(_ context.Context, name string) (*models.TeamSettings, error) {
+	defer m.lock()()
+
+	settings := &models.TeamSettings{TeamName: name}
+	approvals := 0
+	if team, ok := m.data.teams[name]; ok {
+		settings.ReviewerStrategy = team.reviewerStrategy
+		approvals = team.approvalsRequired
+	}
+	settings.ApprovalsRequired = &approvals
+	return settings, nil
+}
+
+func (m *MemoryStorage) SaveTeamSettings(_ context.Context, settings *models.TeamSettings) error {
+	defer m.lock()()
+
+	team, ok := m.data.teams[settings.TeamName]
+	if !ok {
+		return fmt.Errorf("ошибка при сохранении настроек команды %s: %w", settings.TeamName, ErrConflict)
+	}
+	team.reviewerStrategy = settings.ReviewerStrategy
+	team.approvalsRequired = 0
+	if settings.ApprovalsRequired != nil {
+		team.approvalsRequired = *settings.ApprovalsRequired
+	}
+	return nil
+}
+
+func (m *MemoryStorage) AdvanceRoundRobinCursor(_ context.Context, name string, step int) (int64, error) {
+	defer m.lock()()
+
+	team, ok := m.data.teams[name]
+	if !ok {
+		return 0, fmt.Errorf("ошибка при сдвиге курсора round-robin команды %s: %w", name, ErrConflict)
+	}
+	cursor := team.roundRobinCursor
+	team.roundRobinCursor += int64(step)
+	return cursor, nil
+}
+
+// ---------- Users ----------
+
+func (m *MemoryStorage) SaveUser(_ context.Context, user *models.User) error {
+	defer m.lock()()
+
+	if _, ok := m.data.teams[user.TeamName]; !ok {
+		return fmt.Errorf("ошибка при сохранении пользователя: команда %s не найдена: %w", user.TeamName, ErrConflict)
+	}
+	m.data.users[user.UserId] = *user
+	return nil
+}
+
+func (m *MemoryStorage) GetUser(_ context.Context, id string) (*models.User, error) {
+	defer m.lock()()
+
+	user, ok := m.data.users[id]
+	if !ok {
+		return nil, fmt.Errorf("пользователь %s: %w", id, ErrNotFound)
+	}
+	return &user, nil
+}
+
+func (m *MemoryStorage) GetUsersByUsername(_ context.Context, username string) ([]models.User, error) {
+	defer m.lock()()
+
+	var users []models.User
+	for _, user := range m.data.users {
+		if strings.EqualFold(user.Username, username) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserId < users[j].UserId
+	})
+	return users, nil
+}
+
+func (m *MemoryStorage) SaveGitHubUser(_ context.Context, login, userId string) error {
+	defer m.lock()()
+
+	m.data.githubUsers[login] = userId
+	return nil
+}
+
+func (m *MemoryStorage) GetGitHubUser(_ context.Context, login string) (string, error) {
+	defer m.lock()()
+
+	userId, ok := m.data.githubUsers[login]
+	if !ok {
+		return "", fmt.Errorf("логин GitHub %s: %w", login, ErrNotFound)
+	}
+	return userId, nil
+}
+
+// ---------- Pull Requests ----------
+
+func (m *MemoryStorage) PullRequestExists(_ context.Context, id string) (bool, error) {
+	defer m.lock()()
+
+	_, ok := m.data.pullRequests[id]
+	return ok, nil
+}
+
+func (m *MemoryStorage) CreatePullRequest(_ context.Context, pr *models.PullRequest) (bool, error) {
+	defer m.lock()()
+
+	if _, ok := m.data.pullRequests[pr.PullRequestId]; ok {
+		return false, nil
+	}
+	if err := m.checkPullRequestRefs(pr); err != nil {
+		return false, err
+	}
+
+	created := copyPullRequest(*pr)
+	// Вердиктов у нового PR нет, как и у только что вставленных строк pr_reviewers
+	created.Reviews = []models.Review{}
+	m.data.pullRequests[pr.PullRequestId] = created
+	return true, nil
+}
+
+func (m *MemoryStorage) SavePullRequest(_ context.Context, pr *models.PullRequest) error {
+	defer m.lock()()
+
+	if err := m.checkPullRequestRefs(pr); err != nil {
+		return err
+	}
+
+	saved := copyPullRequest(*pr)
+	// created_at и автор при обновлении не меняются, как в ON CONFLICT DO UPDATE;
+	// вердикты меняются только через SaveReview и теряются вместе с назначением
+	saved.Reviews = []models.Review{}
+	if existing, ok := m.data.pullRequests[pr.PullRequestId]; ok {
+		saved.AuthorId = existing.AuthorId
+		saved.CreatedAt = existing.CreatedAt
+		saved.Reviews = assignedReviews(existing.Reviews, saved.AssignedReviewers)
+	}
+	m.data.pullRequests[pr.PullRequestId] = saved
+	return nil
+}
+
+// checkPullRequestRefs повторяет проверку внешних ключей на автора и ревьюверов
+func (m *MemoryStorage) checkPullRequestRefs(pr *models.PullRequest) error {
+	if _, ok := m.data.users[pr.AuthorId]; !ok {
+		return fmt.Errorf("ошибка создания PR: автор %s не найден: %w", pr.AuthorId, ErrConflict)
+	}
+	for _, userId := range pr.AssignedReviewers {
+		if _, ok := m.data.users[userId]; !ok {
+			return fmt.Errorf("ошибка назначения ревьювера (user_id=%s): пользователь не найден: %w", userId, ErrConflict)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) GetPullRequest(_ context.Context, id string) (*models.PullRequest, error) {
+	defer m.lock()()
+
+	pr, ok := m.data.pullRequests[id]
+	if !ok {
+		return nil, fmt.Errorf("PR %s: %w", id, ErrNotFound)
+	}
+	pr = copyPullRequest(pr)
+	return &pr, nil
+}
+
+// GetPullRequestForUpdate не отличается от GetPullRequest: InTx и так держит блокировку всего хранилища
+func (m *MemoryStorage) GetPullRequestForUpdate(ctx context.Context, id string) (*models.PullRequest, error) {
+	return m.GetPullRequest(ctx, id)
+}
+
+func (m *MemoryStorage) SaveReview(_ context.Context, prId string, review models.Review) error {
+	defer m.lock()()
+
+	pr, ok := m.data.pullRequests[prId]
+	if !ok || !slices.Contains(pr.AssignedReviewers, review.ReviewerId) {
+		return fmt.Errorf("ревьювер %s PR %s: %w", review.ReviewerId, prId, ErrNotFound)
+	}
+
+	reviews := slices.DeleteFunc(slices.Clone(pr.Reviews), func(r models.Review) bool {
+		return r.ReviewerId == review.ReviewerId
+	})
+	reviews = append(reviews, review)
+	// Порядок слотов, как в выборке из pr_reviewers
+	slices.SortFunc(reviews, func(a, b models.Review) int {
+		return slices.Index(pr.AssignedReviewers, a.ReviewerId) - slices.Index(pr.AssignedReviewers, b.ReviewerId)
+	})
+	pr.Reviews = reviews
+	m.data.pullRequests[prId] = pr
+	return nil
+}
+
+func (m *MemoryStorage) GetPullRequestsByReviewer(_ context.Context, userId string) ([]models.PullRequest, error) {
+	defer m.lock()()
+
+	var pullRequests []models.PullRequest
+	for _, pr := range m.data.pullRequests {
+		if slices.Contains(pr.AssignedReviewers, userId) {
+			pr = copyPullRequest(pr)
+			// Как и в выборке из БД, даты и вердикты не заполняются
+			pr.CreatedAt, pr.MergedAt, pr.ClosedAt = nil, nil, nil
+			pr.Reviews = nil
+			pullRequests = append(pullRequests, pr)
+		}
+	}
+	sort.Slice(pullRequests, func(i, j int) bool {
+		return pullRequests[i].PullRequestId < pullRequests[j].PullRequestId
+	})
+	return pullRequests, nil
+}
+
+func (m *MemoryStorage) ListPullRequests(_ context.Context, filter PullRequestFilter) ([]models.PullRequest, error) {
+	defer m.lock()()
+
+	pullRequests := []models.PullRequest{}
+	for _, pr := range m.data.pullRequests {
+		key := PageKeyOf(&pr)
+		switch {
+		case filter.AuthorId != nil && pr.AuthorId != *filter.AuthorId,
+			filter.TeamName != nil && m.data.users[pr.AuthorId].TeamName != *filter.TeamName,
+			filter.ReviewerId != nil && !slices.Contains(pr.AssignedReviewers, *filter.ReviewerId),
+			filter.Status != nil && pr.Status != *filter.Status,
+			filter.NameContains != nil && !strings.Contains(strings.ToLower(pr.PullRequestName), strings.ToLower(*filter.NameContains)),
+			filter.CreatedAfter != nil && key.CreatedAt.Before(*filter.CreatedAfter),
+			filter.CreatedBefore != nil && !key.CreatedAt.Before(*filter.CreatedBefore):
+			continue
+		case filter.After != nil && !filter.Descending && !filter.After.Less(key),
+			filter.After != nil && filter.Descending && !key.Less(*filter.After):
+			continue
+		}
+
+		pullRequests = append(pullRequests, copyPullRequest(pr))
+	}
+
+	sort.Slice(pullRequests, func(i, j int) bool {
+		a, b := PageKeyOf(&pullRequests[i]), PageKeyOf(&pullRequests[j])
+		if filter.Descending {
+			return b.Less(a)
+		}
+		return a.Less(b)
+	})
+	if filter.Limit > 0 && len(pullRequests) > filter.Limit {
+		pullRequests = pullRequests[:filter.Limit]
+	}
+	return pullRequests, nil
+}
+
+func (m *MemoryStorage) GetOpenReviewCounts(_ context.Context, userIds []string) (map[string]int, error) {
+	defer m.lock()()
+
+	counts := make(map[string]int, len(userIds))
+	for _, pr := range m.data.pullRequests {
+		if pr.Status != models.PullRequestStatusOPEN {
+			continue
+		}
+		for _, userId := range pr.AssignedReviewers {
+			if slices.Contains(userIds, userId) {
+				counts[userId]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (m *MemoryStorage) SaveReassignment(_ context.Context, prId, oldUserId, newUserId string, at time.Time) error {
+	defer m.lock()()
+
+	if _, ok := m.data.pullRequests[prId]; !ok {
+		return fmt.Errorf("ошибка при сохранении переназначения: PR %s не найден: %w", prId, ErrConflict)
+	}
+	m.data.reassignments = append(m.data.reassignments, memoryReassignment{
+		pullRequestId: prId,
+		oldUserId:     oldUserId,
+		newUserId:     newUserId,
+		reassignedAt:  at,
+	})
+	return nil
+}
+
+// ---------- Pull Request Events ----------
+
+func (m *MemoryStorage) AppendPullRequestEvents(_ context.Context, events []models.PullRequestEvent) error {
+	defer m.lock()()
+
+	for _, e := range events {
+		if _, ok := m.data.pullRequests[e.PullRequestId]; !ok {
+			return fmt.Errorf("ошибка записи события PR: PR %s не найден: %w", e.PullRequestId, ErrConflict)
+		}
+	}
+
+	for i := range events {
+		events[i].EventId = int64(len(m.data.events) + 1)
+		m.data.events = append(m.data.events, events[i])
+	}
+	return nil
+}
+
+func (m *MemoryStorage) GetPullRequestEvents(_ context.Context, prId string) ([]models.PullRequestEvent, error) {
+	defer m.lock()()
+
+	events := []models.PullRequestEvent{}
+	for _, e := range m.data.events {
+		if e.PullRequestId == prId {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// ---------- Stats ----------
+
+func (m *MemoryStorage) GetReviewerStats(_ context.Context, from, to *time.Time, teamName *string) ([]models.ReviewerStats, error) {
+	defer m.lock()()
+
+	stats := []models.ReviewerStats{}
+	for _, user := range m.data.users {
+		if teamName != nil && user.TeamName != *teamName {
+			continue
+		}
+
+		st := models.ReviewerStats{
+			UserId:   user.UserId,
+			Username: user.Username,
+			TeamName: user.TeamName,
+		}
+		for _, pr := range m.data.pullRequests {
+			if !slices.Contains(pr.AssignedReviewers, user.UserId) {
+				continue
+			}
+			created := inWindow(pr.CreatedAt, from, to)
+			if created {
+				st.Assignments++
+			}
+			if created && pr.Status == models.PullRequestStatusOPEN {
+				st.OpenAssignments++
+			}
+			if pr.Status == models.PullRequestStatusMERGED && inWindow(pr.MergedAt, from, to) {
+				st.MergedReviewed++
+			}
+		}
+		for _, ra := range m.data.reassignments {
+			if ra.oldUserId == user.UserId && inWindow(m.data.pullRequests[ra.pullRequestId].CreatedAt, from, to) {
+				st.Assignments++
+				st.ReassignedAway++
+			}
+		}
+		stats = append(stats, st)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TeamName != stats[j].TeamName {
+			return stats[i].TeamName < stats[j].TeamName
+		}
+		return stats[i].UserId < stats[j].UserId
+	})
+	return stats, nil
+}
+
+// inWindow повторяет условие "(from IS NULL OR t >= from) AND (to IS NULL OR t < to)":
+// пустое t попадает в окно только при отсутствии границ
+func inWindow(t, from, to *time.Time) bool {
+	if from != nil && (t == nil || t.Before(*from)) {
+		return false
+	}
+	if to != nil && (t == nil || !t.Before(*to)) {
+		return false
+	}
+	return true
+}
+
+// ---------- Webhooks ----------
+
+func (m *MemoryStorage) CreateWebhookSubscription(_ context.Context, sub *models.WebhookSubscription, secret string) error {
+	defer m.lock()()
+
+	if _, ok := m.data.subscriptions[sub.SubscriptionId]; ok {
+		return fmt.Errorf("подписка %s уже существует: %w", sub.SubscriptionId, ErrConflict)
+	}
+	stored := *sub
+	stored.Events = slices.Clone(sub.Events)
+	m.data.subscriptions[sub.SubscriptionId] = memorySubscription{sub: stored, secret: secret}
+	return nil
+}
+
+func (m *MemoryStorage) ListWebhookSubscriptions(_ context.Context) ([]models.WebhookSubscription, error) {
+	defer m.lock()()
+
+	subs := make([]models.WebhookSubscription, 0, len(m.data.subscriptions))
+	for _, s := range m.data.subscriptions {
+		sub := s.sub
+		sub.Events = slices.Clone(s.sub.Events)
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if !subs[i].CreatedAt.Equal(subs[j].CreatedAt) {
+			return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+		}
+		return subs[i].SubscriptionId < subs[j].SubscriptionId
+	})
+	return subs, nil
+}
+
+func (m *MemoryStorage) DeleteWebhookSubscription(_ context.Context, id string) error {
+	defer m.lock()()
+
+	if _, ok := m.data.subscriptions[id]; !ok {
+		return fmt.Errorf("подписка %s: %w", id, ErrNotFound)
+	}
+	delete(m.data.subscriptions, id)
+	m.data.deliveries = slices.DeleteFunc(m.data.deliveries, func(d memoryDelivery) bool {
+		return d.SubscriptionId == id
+	})
+	return nil
+}
+
+func (m *MemoryStorage) EnqueueWebhook(_ context.Context, event models.WebhookEventType, payload []byte, at time.Time) error {
+	defer m.lock()()
+
+	for _, s := range m.data.subscriptions {
+		if len(s.sub.Events) > 0 && !slices.Contains(s.sub.Events, event) {
+			continue
+		}
+		m.data.lastDeliveryId++
+		m.data.deliveries = append(m.data.deliveries, memoryDelivery{
+			WebhookDelivery: WebhookDelivery{
+				Id:             m.data.lastDeliveryId,
+				SubscriptionId: s.sub.SubscriptionId,
+				Url:            s.sub.Url,
+				Secret:         s.secret,
+				Event:          event,
+				Payload:        slices.Clone(payload),
+			},
+			nextAttemptAt: at,
+		})
+	}
+	return nil
+}
+
+func (m *MemoryStorage) ClaimWebhookDeliveries(_ context.Context, now, leaseUntil time.Time, limit int) ([]WebhookDelivery, error) {
+	defer m.lock()()
+
+	var deliveries []WebhookDelivery
+	for i := range m.data.deliveries {
+		d := &m.data.deliveries[i]
+		if len(deliveries) == limit {
+			break
+		}
+		if d.done || d.nextAttemptAt.After(now) {
+			continue
+		}
+		d.Attempt++
+		d.nextAttemptAt = leaseUntil
+		deliveries = append(deliveries, d.WebhookDelivery)
+	}
+	return deliveries, nil
+}
+
+func (m *MemoryStorage) CompleteWebhookDelivery(_ context.Context, id int64, _ time.Time) error {
+	defer m.lock()()
+
+	if d := m.delivery(id); d != nil {
+		d.done = true
+		d.lastError = ""
+	}
+	return nil
+}
+
+func (m *MemoryStorage) RetryWebhookDelivery(_ context.Context, id int64, lastErr string, next time.Time) error {
+	defer m.lock()()
+
+	if d := m.delivery(id); d != nil {
+		d.lastError = lastErr
+		d.nextAttemptAt = next
+	}
+	return nil
+}
+
+func (m *MemoryStorage) FailWebhookDelivery(_ context.Context, id int64, lastErr string, _ time.Time) error {
+	defer m.lock()()
+
+	if d := m.delivery(id); d != nil {
+		d.lastError = lastErr
+		d.done = true
+	}
+	return nil
+}
+
+// delivery ищет доставку по id; вызывается под блокировкой
+func (m *MemoryStorage) delivery(id int64) *memoryDelivery {
+	for i := range m.data.deliveries {
+		if m.data.deliveries[i].Id == id {
+			return &m.data.deliveries[i]
+		}
+	}
+	return nil
+}
+
+// ---------- Health ----------
+
+func (m *MemoryStorage) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// MigrationVersion всегда возвращает 0: хранилище в памяти не использует миграции
+func (m *MemoryStorage) MigrationVersion(ctx context.Context) (int64, error) {
+	return 0, ctx.Err()
+}
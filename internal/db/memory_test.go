@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-reviewer/internal/models"
+	"sync"
+	"testing"
+)
+
+func newTestMemoryStorage(t *testing.T) *MemoryStorage {
+	t.Helper()
+	ctx := context.Background()
+
+	m := NewMemoryStorage()
+	err := m.SaveTeam(ctx, &models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserId: "author", Username: "Author", IsActive: true},
+			{UserId: "u1", Username: "U1", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	return m
+}
+
+func TestMemoryStorageInTxRollback(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryStorage(t)
+
+	errAbort := errors.New("abort")
+	err := m.InTx(ctx, func(tx Repository) error {
+		pr := &models.PullRequest{PullRequestId: "pr-1", AuthorId: "author", Status: models.PullRequestStatusOPEN}
+		if err := tx.SavePullRequest(ctx, pr); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Ожидалась ошибка транзакции, получена %v", err)
+	}
+
+	if exists, _ := m.PullRequestExists(ctx, "pr-1"); exists {
+		t.Fatal("PR должен быть откачен вместе с транзакцией")
+	}
+}
+
+func TestMemoryStorageReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryStorage(t)
+
+	pr := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		AuthorId:          "author",
+		Status:            models.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"u1"},
+	}
+	if err := m.SavePullRequest(ctx, pr); err != nil {
+		t.Fatalf("Ошибка сохранения PR: %v", err)
+	}
+
+	pr.AssignedReviewers[0] = "author"
+	got, _ := m.GetPullRequest(ctx, "pr-1")
+	got.AssignedReviewers[0] = "author"
+
+	stored, _ := m.GetPullRequest(ctx, "pr-1")
+	if stored.AssignedReviewers[0] != "u1" {
+		t.Fatalf("Изменение копии затронуло хранилище: %v", stored.AssignedReviewers)
+	}
+}
+
+func TestMemoryStorageCascadeOnTeamReplace(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStorage()
+	team := &models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserId: "u1", Username: "U1", IsActive: true},
+			{UserId: "u2", Username: "U2", IsActive: true},
+		},
+	}
+	if err := m.SaveTeam(ctx, team); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	if err := m.SaveTeam(ctx, &models.Team{TeamName: "other", Members: []models.TeamMember{{UserId: "author", Username: "A"}}}); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	pr := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		AuthorId:          "author",
+		Status:            models.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"u1", "u2"},
+	}
+	if err := m.SavePullRequest(ctx, pr); err != nil {
+		t.Fatalf("Ошибка сохранения PR: %v", err)
+	}
+
+	// Пересоздание команды без u2 снимает его с PR
+	team.Members = team.Members[:1]
+	if err := m.SaveTeam(ctx, team); err != nil {
+		t.Fatalf("Ошибка пересоздания команды: %v", err)
+	}
+
+	if _, err := m.GetUser(ctx, "u2"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrNotFound, получена %v", err)
+	}
+	if prs, _ := m.GetPullRequestsByReviewer(ctx, "u2"); len(prs) != 0 {
+		t.Fatalf("Назначения u2 должны быть удалены, найдено %d", len(prs))
+	}
+}
+
+func TestMemoryStorageReviewsFollowAssignment(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryStorage(t)
+
+	pr := &models.PullRequest{
+		PullRequestId:     "pr-1",
+		AuthorId:          "author",
+		Status:            models.PullRequestStatusOPEN,
+		AssignedReviewers: []string{"u1"},
+	}
+	if _, err := m.CreatePullRequest(ctx, pr); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	if err := m.SaveReview(ctx, "pr-1", models.Review{ReviewerId: "author", Verdict: models.APPROVED}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrNotFound для неназначенного ревьювера, получена %v", err)
+	}
+	for _, verdict := range []models.ReviewVerdict{models.CHANGESREQUESTED, models.APPROVED} {
+		if err := m.SaveReview(ctx, "pr-1", models.Review{ReviewerId: "u1", Verdict: verdict}); err != nil {
+			t.Fatalf("Ошибка сохранения вердикта: %v", err)
+		}
+	}
+
+	// Повторный вердикт заменяет предыдущий, а SavePullRequest не трогает вердикты
+	got, _ := m.GetPullRequest(ctx, "pr-1")
+	got.Reviews = nil
+	if err := m.SavePullRequest(ctx, got); err != nil {
+		t.Fatalf("Ошибка сохранения PR: %v", err)
+	}
+	stored, _ := m.GetPullRequest(ctx, "pr-1")
+	if len(stored.Reviews) != 1 || stored.Reviews[0].Verdict != models.APPROVED {
+		t.Fatalf("Ожидался один вердикт APPROVED, получено %+v", stored.Reviews)
+	}
+
+	// Снятый ревьювер теряет вердикт
+	stored.AssignedReviewers = []string{}
+	if err := m.SavePullRequest(ctx, stored); err != nil {
+		t.Fatalf("Ошибка сохранения PR: %v", err)
+	}
+	if stored, _ = m.GetPullRequest(ctx, "pr-1"); len(stored.Reviews) != 0 {
+		t.Fatalf("Вердикт снятого ревьювера должен быть удалён: %+v", stored.Reviews)
+	}
+}
+
+func TestMemoryStorageConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMemoryStorage(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pr := &models.PullRequest{
+				PullRequestId:     fmt.Sprintf("pr-%d", i),
+				AuthorId:          "author",
+				Status:            models.PullRequestStatusOPEN,
+				AssignedReviewers: []string{"u1"},
+			}
+			_ = m.InTx(ctx, func(tx Repository) error {
+				return tx.SavePullRequest(ctx, pr)
+			})
+			_, _ = m.GetOpenReviewCounts(ctx, []string{"u1"})
+		}(i)
+	}
+	wg.Wait()
+
+	counts, err := m.GetOpenReviewCounts(ctx, []string{"u1"})
+	if err != nil {
+		t.Fatalf("Ошибка подсчёта: %v", err)
+	}
+	if counts["u1"] != 50 {
+		t.Fatalf("Ожидалось 50 открытых ревью, получено %d", counts["u1"])
+	}
+}
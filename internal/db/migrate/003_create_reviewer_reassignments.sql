@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reviewer_reassignments (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_user_id TEXT NOT NULL,
+    new_user_id TEXT NOT NULL,
+    reassigned_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_reassignments_old_user ON reviewer_reassignments (old_user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS reviewer_reassignments;
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT now(),
+    -- позиция ревьювера в списке assigned_reviewers
+    slot SMALLINT NOT NULL,
+    PRIMARY KEY (pull_request_id, user_id),
+    CONSTRAINT pr_reviewers_slot_key UNIQUE (pull_request_id, slot) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- Поиск PR по ревьюверу; pull_request_id в индексе позволяет обойтись без чтения таблицы
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user ON pr_reviewers (user_id, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status ON pull_requests (status);
+
+-- Переносим назначения из JSONB; ревьюверы, которых уже нет в users, отбрасываются
+INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at, slot)
+SELECT pr.pull_request_id, r.user_id, COALESCE(pr.created_at, now()), r.ord - 1
+FROM pull_requests pr,
+     jsonb_array_elements_text(pr.assigned_reviewers) WITH ORDINALITY AS r(user_id, ord)
+WHERE EXISTS (SELECT 1 FROM users u WHERE u.user_id = r.user_id)
+ON CONFLICT (pull_request_id, user_id) DO NOTHING;
+
+ALTER TABLE pull_requests DROP COLUMN assigned_reviewers;
+
+-- +goose Down
+ALTER TABLE pull_requests ADD COLUMN assigned_reviewers JSONB NOT NULL DEFAULT '[]';
+
+UPDATE pull_requests pr SET assigned_reviewers = COALESCE((
+    SELECT jsonb_agg(r.user_id ORDER BY r.slot)
+    FROM pr_reviewers r
+    WHERE r.pull_request_id = pr.pull_request_id
+), '[]'::jsonb);
+
+DROP INDEX IF EXISTS idx_pull_requests_status;
+DROP TABLE IF EXISTS pr_reviewers;
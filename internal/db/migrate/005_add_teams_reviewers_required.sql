@@ -0,0 +1,7 @@
+-- +goose Up
+-- NULL означает число ревьюверов из конфигурации сервиса
+ALTER TABLE teams ADD COLUMN reviewers_required SMALLINT
+    CONSTRAINT teams_reviewers_required_check CHECK (reviewers_required BETWEEN 1 AND 10);
+
+-- +goose Down
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewers_required;
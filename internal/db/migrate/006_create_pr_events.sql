@@ -0,0 +1,31 @@
+-- +goose Up
+-- Журнал изменений PR: строки только добавляются
+CREATE TABLE IF NOT EXISTS pr_events (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id),
+    event_type TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    reviewer_id TEXT,
+    old_reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_pull_request ON pr_events (pull_request_id, id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION pr_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'pr_events допускает только добавление строк';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER pr_events_append_only
+    BEFORE UPDATE OR DELETE ON pr_events
+    FOR EACH ROW EXECUTE FUNCTION pr_events_append_only();
+
+-- +goose Down
+DROP TABLE IF EXISTS pr_events;
+DROP FUNCTION IF EXISTS pr_events_append_only();
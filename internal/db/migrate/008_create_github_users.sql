@@ -0,0 +1,10 @@
+-- +goose Up
+-- Сопоставление логинов GitHub пользователям сервиса; логин хранится в нижнем регистре.
+-- Внешнего ключа нет: SaveTeam пересоздаёт участников команды, и каскад удалил бы сопоставления.
+CREATE TABLE IF NOT EXISTS github_users (
+    github_login TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS github_users;
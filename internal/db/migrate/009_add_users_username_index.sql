@@ -0,0 +1,6 @@
+-- +goose Up
+-- Поиск пользователя по username для интеграции с GitLab; username не уникален и сравнивается без учёта регистра.
+CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users (lower(username));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_username_lower;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Время закрытия PR без merge (статус CLOSED); при повторном открытии сбрасывается в NULL.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+-- +goose Down
+UPDATE pull_requests SET status = 'OPEN' WHERE status = 'CLOSED';
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Черновик PR: ревьюверы назначаются только после /pullRequest/markReady.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS draft BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS draft;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Последний вердикт ревьювера хранится в строке назначения и удаляется вместе с ней
+ALTER TABLE pr_reviewers
+    ADD COLUMN IF NOT EXISTS verdict TEXT,
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+ALTER TABLE pr_events ADD COLUMN IF NOT EXISTS verdict TEXT;
+
+-- 0 отключает проверку одобрений перед merge
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS approvals_required SMALLINT NOT NULL DEFAULT 0
+    CONSTRAINT team_settings_approvals_required_check CHECK (approvals_required BETWEEN 0 AND 10);
+
+-- +goose Down
+ALTER TABLE team_settings DROP COLUMN IF EXISTS approvals_required;
+ALTER TABLE pr_events DROP COLUMN IF EXISTS verdict;
+ALTER TABLE pr_reviewers
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS verdict;
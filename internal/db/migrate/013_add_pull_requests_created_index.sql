@@ -0,0 +1,7 @@
+-- +goose Up
+-- Порядок выдачи списков PR: created_at (PR без даты считаются самыми старыми), затем pull_request_id
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created
+    ON pull_requests ((COALESCE(created_at, 'epoch'::timestamp)), pull_request_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pull_requests_created;
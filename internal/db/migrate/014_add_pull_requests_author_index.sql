@@ -0,0 +1,6 @@
+-- +goose Up
+-- Фильтры /pullRequest/list по автору и по команде автора
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author ON pull_requests (author_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pull_requests_author;
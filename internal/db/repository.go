@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"pr-reviewer/internal/models"
+	"time"
+)
+
+// Repository описывает хранилище, которым пользуется сервис.
+// Реализации: Storage (PostgreSQL) и MemoryStorage.
+// Ошибки содержат в цепочке ErrNotFound, ErrConflict или ErrUnavailable, если причина известна.
+type Repository interface {
+	// InTx выполняет fn в одной транзакции; при ошибке fn изменения откатываются
+	InTx(ctx context.Context, fn func(tx Repository) error) error
+
+	TeamExists(ctx context.Context, name string) (bool, error)
+	SaveTeam(ctx context.Context, team *models.Team) error
+	GetTeam(ctx context.Context, name string) (*models.Team, error)
+	SetTeamReviewersRequired(ctx context.Context, name string, count int) error
+	// GetTeamSettings всегда заполняет ApprovalsRequired; 0 отключает проверку одобрений
+	GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error)
+	SaveTeamSettings(ctx context.Context, settings *models.TeamSettings) error
+	AdvanceRoundRobinCursor(ctx context.Context, name string, step int) (int64, error)
+
+	SaveUser(ctx context.Context, user *models.User) error
+	GetUser(ctx context.Context, id string) (*models.User, error)
+	// GetUsersByUsername возвращает пользователей с данным username без учёта регистра, по возрастанию user_id
+	GetUsersByUsername(ctx context.Context, username string) ([]models.User, error)
+	// SaveGitHubUser сопоставляет логин GitHub пользователю; login передаётся в нижнем регистре
+	SaveGitHubUser(ctx context.Context, login, userId string) error
+	GetGitHubUser(ctx context.Context, login string) (string, error)
+
+	PullRequestExists(ctx context.Context, id string) (bool, error)
+	CreatePullRequest(ctx context.Context, pr *models.PullRequest) (bool, error)
+	SavePullRequest(ctx context.Context, pr *models.PullRequest) error
+	GetPullRequest(ctx context.Context, id string) (*models.PullRequest, error)
+	// GetPullRequestForUpdate читает PR и блокирует его строку до конца транзакции,
+	// чтобы параллельные изменения статуса и ревьюверов выполнялись по очереди
+	GetPullRequestForUpdate(ctx context.Context, id string) (*models.PullRequest, error)
+	// SaveReview записывает вердикт назначенного ревьювера, заменяя предыдущий.
+	// Если ревьювер не назначен на PR, возвращает ErrNotFound.
+	SaveReview(ctx context.Context, prId string, review models.Review) error
+	GetPullRequestsByReviewer(ctx context.Context, userId string) ([]models.PullRequest, error)
+	// ListPullRequests возвращает PR, подходящие под filter, в порядке PageKey
+	ListPullRequests(ctx context.Context, filter PullRequestFilter) ([]models.PullRequest, error)
+	GetOpenReviewCounts(ctx context.Context, userIds []string) (map[string]int, error)
+	SaveReassignment(ctx context.Context, prId, oldUserId, newUserId string, at time.Time) error
+	// AppendPullRequestEvents дописывает события в журнал PR; EventId заполняется хранилищем
+	AppendPullRequestEvents(ctx context.Context, events []models.PullRequestEvent) error
+	GetPullRequestEvents(ctx context.Context, prId string) ([]models.PullRequestEvent, error)
+
+	GetReviewerStats(ctx context.Context, from, to *time.Time, teamName *string) ([]models.ReviewerStats, error)
+
+	CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription, secret string) error
+	ListWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	// EnqueueWebhook добавляет в outbox доставку payload каждой подписке на event.
+	// Вызывается в транзакции изменения, чтобы событие не потерялось и не ушло при откате.
+	EnqueueWebhook(ctx context.Context, event models.WebhookEventType, payload []byte, at time.Time) error
+	// ClaimWebhookDeliveries выбирает до limit доставок со сроком не позже now, увеличивает
+	// их счётчик попыток и откладывает до leaseUntil: если процесс упадёт во время отправки,
+	// доставка повторится после leaseUntil
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]WebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, id int64, at time.Time) error
+	// RetryWebhookDelivery запоминает ошибку и назначает следующую попытку на next
+	RetryWebhookDelivery(ctx context.Context, id int64, lastErr string, next time.Time) error
+	// FailWebhookDelivery прекращает попытки доставки
+	FailWebhookDelivery(ctx context.Context, id int64, lastErr string, at time.Time) error
+
+	// Ping проверяет, что хранилище доступно
+	Ping(ctx context.Context) error
+	// MigrationVersion возвращает версию последней применённой миграции схемы
+	MigrationVersion(ctx context.Context) (int64, error)
+}
+
+// WebhookDelivery — доставка события из outbox вместе с адресом и секретом подписки
+type WebhookDelivery struct {
+	Id             int64
+	SubscriptionId string
+	Url            string
+	Secret         string
+	Event          models.WebhookEventType
+	Payload        []byte
+	// Attempt — номер текущей попытки, начиная с 1
+	Attempt int
+}
+
+// PullRequestFilter — условия выборки ListPullRequests; пустые поля не ограничивают выборку
+type PullRequestFilter struct {
+	AuthorId *string
+	// TeamName — команда автора PR
+	TeamName   *string
+	ReviewerId *string
+	Status     *models.PullRequestStatus
+	// NameContains — подстрока названия PR без учёта регистра
+	NameContains *string
+	// CreatedAfter и CreatedBefore задают окно [CreatedAfter, CreatedBefore) по PageKey.CreatedAt
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Descending    bool
+	// After — ключ последнего PR предыдущей страницы; выдача продолжается строго после него
+	After *PageKey
+	// Limit ограничивает количество PR; 0 снимает ограничение
+	Limit int
+}
+
+// PageKey — позиция PR в списке: created_at, затем pull_request_id.
+// PR без created_at считаются созданными в начале эпохи Unix.
+type PageKey struct {
+	CreatedAt     time.Time
+	PullRequestId string
+}
+
+// PageKeyOf возвращает позицию pr в списке
+func PageKeyOf(pr *models.PullRequest) PageKey {
+	key := PageKey{CreatedAt: time.Unix(0, 0).UTC(), PullRequestId: pr.PullRequestId}
+	if pr.CreatedAt != nil {
+		key.CreatedAt = *pr.CreatedAt
+	}
+	return key
+}
+
+// Less сообщает, идёт ли k раньше other при сортировке по возрастанию
+func (k PageKey) Less(other PageKey) bool {
+	if !k.CreatedAt.Equal(other.CreatedAt) {
+		return k.CreatedAt.Before(other.CreatedAt)
+	}
+	return k.PullRequestId < other.PullRequestId
+}
+
+var (
+	_ Repository = (*Storage)(nil)
+	_ Repository = (*MemoryStorage)(nil)
+)
@@ -1,51 +1,90 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"pr-reviewer/internal/models"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// querier — общее подмножество *sql.DB и *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // Storage реализует слой доступа к данным (PostgreSQL)
 type Storage struct {
 	db *sql.DB
+	// tx задана, если Storage получен внутри InTx
+	tx *sql.Tx
+}
+
+// PoolOptions ограничивает пул соединений; нулевые значения оставляют настройки database/sql по умолчанию
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
 // NewStorage открывает соединение с PostgreSQL и создаёт структуру Storage
-func NewStorage(connString string) (*Storage, error) {
+func NewStorage(ctx context.Context, connString string, pool PoolOptions) (*Storage, error) {
 	db, err := sql.Open("pgx", connString)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка подключения к БД: %w", err)
+		return nil, fmt.Errorf("ошибка подключения к БД: %w", classify(err))
+	}
+
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
 	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("БД недоступна: %w", err)
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("БД недоступна: %w", classify(err))
 	}
 
 	s := &Storage{db: db}
 	return s, nil
 }
 
-// ---------- Team ----------
+// Close закрывает пул соединений. Вызывается один раз при остановке приложения
+// на Storage, созданном NewStorage, после завершения всех запросов.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
 
-func (s *Storage) TeamExists(name string) (bool, error) {
-	var exists bool
-	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, name).Scan(&exists)
+// Stats возвращает статистику пула соединений
+func (s *Storage) Stats() sql.DBStats {
+	return s.db.Stats()
+}
 
-	if err != nil {
-		return false, fmt.Errorf("ошибка при проверке существования команды: %w", err)
-	}
-	return exists, nil
+// InTx выполняет fn в одной транзакции: все вызовы через переданный tx
+// либо фиксируются вместе, либо откатываются, если fn вернула ошибку.
+// Вложенный вызов InTx переиспользует текущую транзакцию.
+func (s *Storage) InTx(ctx context.Context, fn func(tx Repository) error) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return fn(&Storage{db: s.db, tx: tx})
+	})
 }
 
-func (s *Storage) SaveTeam(team *models.Team) error {
-	tx, err := s.db.Begin()
+// withTx выполняет fn в текущей транзакции, а если её нет — в новой
+func (s *Storage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("ошибка начала транзакции: %w", err)
+		return fmt.Errorf("ошибка начала транзакции: %w", classify(err))
 	}
 	// Откатываем, если err != nil к моменту выхода из функции (или коммит не удался)
 	defer func() {
@@ -54,66 +93,202 @@ func (s *Storage) SaveTeam(team *models.Team) error {
 		}
 	}()
 
-	if _, err = tx.Exec(`INSERT INTO teams (team_name) VALUES ($1) ON CONFLICT (team_name) DO NOTHING`, team.TeamName); err != nil {
-		return fmt.Errorf("ошибка вставки команды: %w", err)
+	if err = fn(tx); err != nil {
+		return err
 	}
 
-	// Удалим старых участников, чтобы пересоздать
-	if _, err = tx.Exec(`DELETE FROM users WHERE team_name=$1`, team.TeamName); err != nil {
-		return fmt.Errorf("ошибка удаления предыдущих участников: %w", err)
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка коммита транзакции: %w", classify(err))
 	}
+	return nil
+}
 
-	for _, m := range team.Members {
-		if _, err = tx.Exec(`
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1, $2, $3, $4)
-			ON CONFLICT (user_id) DO UPDATE SET
-				username=EXCLUDED.username,
-				team_name=EXCLUDED.team_name,
-				is_active=EXCLUDED.is_active`,
-			m.UserId, m.Username, team.TeamName, m.IsActive,
-		); err != nil {
-			return fmt.Errorf("ошибка вставки участника (user_id=%s): %w", m.UserId, err)
-		}
+// q возвращает транзакцию, если Storage работает внутри неё, иначе пул соединений
+func (s *Storage) q() querier {
+	if s.tx != nil {
+		return s.tx
 	}
+	return s.db
+}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("ошибка коммита транзакции: %w", err)
+// ---------- Team ----------
+
+func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := s.q().QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name=$1)`, name).Scan(&exists)
+
+	if err != nil {
+		return false, fmt.Errorf("ошибка при проверке существования команды: %w", classify(err))
 	}
+	return exists, nil
+}
 
-	return nil
+func (s *Storage) SaveTeam(ctx context.Context, team *models.Team) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO teams (team_name, reviewers_required) VALUES ($1, $2)
+			ON CONFLICT (team_name) DO UPDATE SET
+				reviewers_required=COALESCE(EXCLUDED.reviewers_required, teams.reviewers_required)`,
+			team.TeamName, team.ReviewersRequired,
+		); err != nil {
+			return fmt.Errorf("ошибка вставки команды: %w", classify(err))
+		}
+
+		// Удалим старых участников, чтобы пересоздать
+		if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE team_name=$1`, team.TeamName); err != nil {
+			return fmt.Errorf("ошибка удаления предыдущих участников: %w", classify(err))
+		}
+
+		for _, m := range team.Members {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO users (user_id, username, team_name, is_active)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (user_id) DO UPDATE SET
+					username=EXCLUDED.username,
+					team_name=EXCLUDED.team_name,
+					is_active=EXCLUDED.is_active`,
+				m.UserId, m.Username, team.TeamName, m.IsActive,
+			); err != nil {
+				return fmt.Errorf("ошибка вставки участника (user_id=%s): %w", m.UserId, classify(err))
+			}
+		}
+		return nil
+	})
 }
 
-func (s *Storage) GetTeam(name string) (*models.Team, error) {
-	rows, err := s.db.Query(`SELECT user_id, username, is_active FROM users WHERE team_name=$1`, name)
+// GetTeam возвращает команду с участниками или ErrNotFound, если команды нет
+func (s *Storage) GetTeam(ctx context.Context, name string) (*models.Team, error) {
+	// LEFT JOIN отличает команду без участников (одна строка с NULL) от несуществующей (нет строк)
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT t.reviewers_required, u.user_id, u.username, u.is_active
+		FROM teams t
+		LEFT JOIN users u ON u.team_name = t.team_name
+		WHERE t.team_name=$1
+		ORDER BY u.user_id`, name)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("ошибка при получении команды: %w", classify(err))
 	}
 	defer rows.Close() //nolint:errcheck
 
+	found := false
+	var reviewersRequired sql.NullInt32
 	var members []models.TeamMember
 	for rows.Next() {
-		var m models.TeamMember
-		if err := rows.Scan(&m.UserId, &m.Username, &m.IsActive); err != nil {
-			return nil, fmt.Errorf("ошибка при сканировании участника %w", err)
+		found = true
+
+		var userId, username sql.NullString
+		var isActive sql.NullBool
+		if err := rows.Scan(&reviewersRequired, &userId, &username, &isActive); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании участника %w", classify(err))
 		}
-		members = append(members, m)
+		if !userId.Valid {
+			continue
+		}
+		members = append(members, models.TeamMember{
+			UserId:   userId.String,
+			Username: username.String,
+			IsActive: isActive.Bool,
+		})
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+
+	if !found {
+		return nil, fmt.Errorf("команда %s: %w", name, ErrNotFound)
 	}
 
-	return &models.Team{
+	team := &models.Team{
 		TeamName: name,
 		Members:  members,
+	}
+	if reviewersRequired.Valid {
+		count := int(reviewersRequired.Int32)
+		team.ReviewersRequired = &count
+	}
+	return team, nil
+}
+
+// SetTeamReviewersRequired задаёт число ревьюверов команды или возвращает ErrNotFound, если команды нет
+func (s *Storage) SetTeamReviewersRequired(ctx context.Context, name string, count int) error {
+	res, err := s.q().ExecContext(ctx, `UPDATE teams SET reviewers_required=$2 WHERE team_name=$1`, name, count)
+	if err != nil {
+		return fmt.Errorf("ошибка при изменении числа ревьюверов команды: %w", classify(err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка при изменении числа ревьюверов команды: %w", classify(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("команда %s: %w", name, ErrNotFound)
+	}
+	return nil
+}
+
+// GetTeamSettings возвращает настройки команды.
+// Если настройки не задавались, ReviewerStrategy остаётся пустой.
+func (s *Storage) GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error) {
+	var strategy sql.NullString
+	var approvals int
+	err := s.q().QueryRowContext(ctx, `
+		SELECT reviewer_strategy, approvals_required FROM team_settings WHERE team_name=$1`, name,
+	).Scan(&strategy, &approvals)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("ошибка при получении настроек команды: %w", classify(err))
+	}
+
+	return &models.TeamSettings{
+		TeamName:          name,
+		ReviewerStrategy:  models.ReviewerStrategy(strategy.String),
+		ApprovalsRequired: &approvals,
 	}, nil
 }
 
+// SaveTeamSettings сохраняет настройки команды; nil ApprovalsRequired записывается как 0
+func (s *Storage) SaveTeamSettings(ctx context.Context, settings *models.TeamSettings) error {
+	approvals := 0
+	if settings.ApprovalsRequired != nil {
+		approvals = *settings.ApprovalsRequired
+	}
+
+	_, err := s.q().ExecContext(ctx, `
+		INSERT INTO team_settings (team_name, reviewer_strategy, approvals_required)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_name) DO UPDATE SET
+			reviewer_strategy=EXCLUDED.reviewer_strategy,
+			approvals_required=EXCLUDED.approvals_required`,
+		settings.TeamName, settings.ReviewerStrategy, approvals,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении настроек команды: %w", classify(err))
+	}
+	return nil
+}
+
+// AdvanceRoundRobinCursor сдвигает курсор round-robin команды на step
+// и возвращает его значение до сдвига.
+func (s *Storage) AdvanceRoundRobinCursor(ctx context.Context, name string, step int) (int64, error) {
+	var cursor int64
+	err := s.q().QueryRowContext(ctx, `
+		INSERT INTO team_settings (team_name, round_robin_cursor)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET
+			round_robin_cursor=team_settings.round_robin_cursor + EXCLUDED.round_robin_cursor
+		RETURNING round_robin_cursor - $2`,
+		name, step,
+	).Scan(&cursor)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при сдвиге курсора round-robin: %w", classify(err))
+	}
+	return cursor, nil
+}
+
 // ---------- Users ----------
 
-func (s *Storage) SaveUser(user *models.User) error {
-	_, err := s.db.Exec(`
+func (s *Storage) SaveUser(ctx context.Context, user *models.User) error {
+	_, err := s.q().ExecContext(ctx, `
 		INSERT INTO users (user_id, username, team_name, is_active)
 		VALUES ($1, $2, $3, $4)
 		ON CONFLICT (user_id) DO UPDATE SET
@@ -124,13 +299,13 @@ func (s *Storage) SaveUser(user *models.User) error {
 	)
 
 	if err != nil {
-		return fmt.Errorf("ошибка при сохранении пользователя: %w", err)
+		return fmt.Errorf("ошибка при сохранении пользователя: %w", classify(err))
 	}
 	return nil
 }
 
-func (s *Storage) GetUser(id string) (*models.User, error) {
-	row := s.db.QueryRow(
+func (s *Storage) GetUser(ctx context.Context, id string) (*models.User, error) {
+	row := s.q().QueryRowContext(ctx,
 		`SELECT user_id, username, team_name, is_active FROM users WHERE user_id=$1`,
 		id,
 	)
@@ -140,99 +315,290 @@ func (s *Storage) GetUser(id string) (*models.User, error) {
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("пользователь %s не найден", id)
+			return nil, fmt.Errorf("пользователь %s: %w", id, ErrNotFound)
 		}
-		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при получении пользователя: %w", classify(err))
 	}
 
 	return &u, nil
 }
 
+func (s *Storage) GetUsersByUsername(ctx context.Context, username string) ([]models.User, error) {
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT user_id, username, team_name, is_active FROM users
+		WHERE lower(username)=lower($1)
+		ORDER BY user_id`,
+		username,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователей по username: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.UserId, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+			return nil, fmt.Errorf("ошибка при чтении пользователя: %w", classify(err))
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователей по username: %w", classify(err))
+	}
+	return users, nil
+}
+
+func (s *Storage) SaveGitHubUser(ctx context.Context, login, userId string) error {
+	_, err := s.q().ExecContext(ctx, `
+		INSERT INTO github_users (github_login, user_id) VALUES ($1, $2)
+		ON CONFLICT (github_login) DO UPDATE SET user_id=EXCLUDED.user_id`,
+		login, userId,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении логина GitHub: %w", classify(err))
+	}
+	return nil
+}
+
+func (s *Storage) GetGitHubUser(ctx context.Context, login string) (string, error) {
+	var userId string
+	err := s.q().QueryRowContext(ctx, `SELECT user_id FROM github_users WHERE github_login=$1`, login).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("логин GitHub %s: %w", login, ErrNotFound)
+		}
+		return "", fmt.Errorf("ошибка при получении логина GitHub: %w", classify(err))
+	}
+	return userId, nil
+}
+
 // ---------- Pull Requests ----------
 
-func (s *Storage) PullRequestExists(id string) (bool, error) {
+func (s *Storage) PullRequestExists(ctx context.Context, id string) (bool, error) {
 	var exists bool
-	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id=$1)`, id).Scan(&exists)
+	err := s.q().QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id=$1)`, id).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("ошибка при проверке существования pull request: %w", err)
+		return false, fmt.Errorf("ошибка при проверке существования pull request: %w", classify(err))
 	}
 	return exists, nil
 }
 
-// TODO: делать rollback если err != nil
-func (s *Storage) SavePullRequest(pr *models.PullRequest) error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return fmt.Errorf("ошибка начала транзакции: %w", err)
-	}
-	defer func() {
+// reviewersColumn собирает ревьюверов PR (алиас pr) в JSON-массив в порядке слотов
+const reviewersColumn = `COALESCE((
+	SELECT jsonb_agg(r.user_id ORDER BY r.slot)
+	FROM pr_reviewers r
+	WHERE r.pull_request_id = pr.pull_request_id
+), '[]'::jsonb)`
+
+// CreatePullRequest вставляет новый PR с ревьюверами.
+// Возвращает false, если PR с таким идентификатором уже есть; существующий PR не меняется.
+func (s *Storage) CreatePullRequest(ctx context.Context, pr *models.PullRequest) (bool, error) {
+	created := false
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		// Уникальность гарантирует первичный ключ: конкурентная вставка ждёт коммита первой и ничего не вставляет
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO pull_requests (
+				pull_request_id, pull_request_name, author_id,
+				status, draft, created_at, merged_at, closed_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (pull_request_id) DO NOTHING`,
+			pr.PullRequestId, pr.PullRequestName, pr.AuthorId,
+			pr.Status, pr.Draft, pr.CreatedAt, pr.MergedAt, pr.ClosedAt,
+		)
 		if err != nil {
-			_ = tx.Rollback()
+			return fmt.Errorf("ошибка создания PR: %w", classify(err))
 		}
-	}()
 
-	// сериализуем массив ревьюверов в JSONB
-	reviewersJSON, _ := json.Marshal(pr.AssignedReviewers)
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("ошибка создания PR: %w", classify(err))
+		}
+		if inserted == 0 {
+			return nil
+		}
 
-	if _, err = tx.Exec(`
-		INSERT INTO pull_requests (
-			pull_request_id, pull_request_name, author_id,
-			assigned_reviewers, status, created_at, merged_at
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		ON CONFLICT (pull_request_id) DO UPDATE SET
-			pull_request_name=EXCLUDED.pull_request_name,
-			assigned_reviewers=EXCLUDED.assigned_reviewers,
-			status=EXCLUDED.status,
-			merged_at=EXCLUDED.merged_at`,
-		pr.PullRequestId, pr.PullRequestName, pr.AuthorId,
-		reviewersJSON, pr.Status, pr.CreatedAt, pr.MergedAt,
-	); err != nil {
-		return fmt.Errorf("ошибка создания PR: %w", err)
+		created = true
+		return saveReviewers(ctx, tx, pr.PullRequestId, pr.AssignedReviewers)
+	})
+	if err != nil {
+		return false, err
 	}
+	return created, nil
+}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("ошибка коммита транзакции: %w", err)
+func (s *Storage) SavePullRequest(ctx context.Context, pr *models.PullRequest) error {
+	// nil-срез ушёл бы в БД как NULL, и "<> ALL" ничего бы не удалил
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
 	}
 
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO pull_requests (
+				pull_request_id, pull_request_name, author_id,
+				status, draft, created_at, merged_at, closed_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (pull_request_id) DO UPDATE SET
+				pull_request_name=EXCLUDED.pull_request_name,
+				status=EXCLUDED.status,
+				draft=EXCLUDED.draft,
+				merged_at=EXCLUDED.merged_at,
+				closed_at=EXCLUDED.closed_at`,
+			pr.PullRequestId, pr.PullRequestName, pr.AuthorId,
+			pr.Status, pr.Draft, pr.CreatedAt, pr.MergedAt, pr.ClosedAt,
+		); err != nil {
+			return fmt.Errorf("ошибка создания PR: %w", classify(err))
+		}
+
+		// Снятых ревьюверов удаляем, у оставшихся сохраняется assigned_at
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM pr_reviewers
+			WHERE pull_request_id=$1 AND user_id <> ALL($2)`,
+			pr.PullRequestId, reviewers,
+		); err != nil {
+			return fmt.Errorf("ошибка удаления ревьюверов PR: %w", classify(err))
+		}
+
+		return saveReviewers(ctx, tx, pr.PullRequestId, reviewers)
+	})
+}
+
+// saveReviewers записывает ревьюверов PR в порядке слотов
+func saveReviewers(ctx context.Context, tx *sql.Tx, prId string, reviewers []string) error {
+	for slot, userId := range reviewers {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO pr_reviewers (pull_request_id, user_id, slot)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (pull_request_id, user_id) DO UPDATE SET
+				slot=EXCLUDED.slot`,
+			prId, userId, slot,
+		); err != nil {
+			return fmt.Errorf("ошибка назначения ревьювера (user_id=%s): %w", userId, classify(err))
+		}
+	}
 	return nil
 }
 
-func (s *Storage) GetPullRequest(id string) (*models.PullRequest, bool) {
-	row := s.db.QueryRow(`
-		SELECT pull_request_id, pull_request_name, author_id,
-		       assigned_reviewers, status, created_at, merged_at
-		FROM pull_requests WHERE pull_request_id=$1`, id)
+func (s *Storage) GetPullRequest(ctx context.Context, id string) (*models.PullRequest, error) {
+	return s.getPullRequest(ctx, id, "")
+}
+
+// GetPullRequestForUpdate вне транзакции блокирует строку только на время запроса
+func (s *Storage) GetPullRequestForUpdate(ctx context.Context, id string) (*models.PullRequest, error) {
+	return s.getPullRequest(ctx, id, "FOR UPDATE OF pr")
+}
+
+func (s *Storage) getPullRequest(ctx context.Context, id, lock string) (*models.PullRequest, error) {
+	row := s.q().QueryRowContext(ctx, `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id,
+		       `+reviewersColumn+`, pr.status, pr.draft, pr.created_at, pr.merged_at, pr.closed_at
+		FROM pull_requests pr WHERE pr.pull_request_id=$1 `+lock, id)
 
 	var pr models.PullRequest
 	var reviewersJSON []byte
 
 	if err := row.Scan(
 		&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId,
-		&reviewersJSON, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
+		&reviewersJSON, &pr.Status, &pr.Draft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt,
 	); err != nil {
-		return nil, false
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("PR %s: %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("ошибка при получении PR: %w", classify(err))
 	}
 
-	err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers)
+	if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+		return nil, fmt.Errorf("ошибка при разборе ревьюверов PR %s: %w", id, err)
+	}
+
+	pullRequests := []models.PullRequest{pr}
+	if err := s.fillReviews(ctx, pullRequests); err != nil {
+		return nil, err
+	}
+	return &pullRequests[0], nil
+}
+
+// fillReviews заполняет вердикты ревьюверов PR в порядке слотов одним запросом
+func (s *Storage) fillReviews(ctx context.Context, pullRequests []models.PullRequest) error {
+	if len(pullRequests) == 0 {
+		return nil
+	}
+
+	reviews := make(map[string][]models.Review, len(pullRequests))
+	prIds := make([]string, 0, len(pullRequests))
+	for _, pr := range pullRequests {
+		prIds = append(prIds, pr.PullRequestId)
+	}
+
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT pull_request_id, user_id, verdict, reviewed_at
+		FROM pr_reviewers
+		WHERE pull_request_id = ANY($1) AND verdict IS NOT NULL
+		ORDER BY pull_request_id, slot`, prIds)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении вердиктов PR: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var prId string
+		var review models.Review
+		if err := rows.Scan(&prId, &review.ReviewerId, &review.Verdict, &review.SubmittedAt); err != nil {
+			return fmt.Errorf("ошибка при сканировании вердикта: %w", classify(err))
+		}
+		reviews[prId] = append(reviews[prId], review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+
+	for i := range pullRequests {
+		pullRequests[i].Reviews = reviews[pullRequests[i].PullRequestId]
+		if pullRequests[i].Reviews == nil {
+			pullRequests[i].Reviews = []models.Review{}
+		}
+	}
+	return nil
+}
+
+func (s *Storage) SaveReview(ctx context.Context, prId string, review models.Review) error {
+	res, err := s.q().ExecContext(ctx, `
+		UPDATE pr_reviewers SET verdict=$3, reviewed_at=$4
+		WHERE pull_request_id=$1 AND user_id=$2`,
+		prId, review.ReviewerId, review.Verdict, review.SubmittedAt,
+	)
 	if err != nil {
-		return nil, false
+		return fmt.Errorf("ошибка при сохранении вердикта: %w", classify(err))
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении вердикта: %w", classify(err))
+	}
+	if updated == 0 {
+		return fmt.Errorf("ревьювер %s PR %s: %w", review.ReviewerId, prId, ErrNotFound)
 	}
-	return &pr, true
+	return nil
 }
 
-func (s *Storage) GetPullRequestsByReviewer(userId string) []models.PullRequest {
+func (s *Storage) GetPullRequestsByReviewer(ctx context.Context, userId string) ([]models.PullRequest, error) {
 	var pullRequests []models.PullRequest
 
-	rows, err := s.db.Query(`
-        SELECT pull_request_id, pull_request_name, author_id,
-               assigned_reviewers, status
-        FROM pull_requests 
-        WHERE jsonb_exists(assigned_reviewers::jsonb, $1)`, userId)
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id,
+		       `+reviewersColumn+`, pr.status
+		FROM pr_reviewers rv
+		JOIN pull_requests pr ON pr.pull_request_id = rv.pull_request_id
+		WHERE rv.user_id = $1`, userId)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("ошибка при получении PR ревьювера: %w", classify(err))
 	}
-	defer rows.Close()
+	defer rows.Close() //nolint:errcheck
 
 	for rows.Next() {
 		var pr models.PullRequest
@@ -242,16 +608,447 @@ func (s *Storage) GetPullRequestsByReviewer(userId string) []models.PullRequest
 			&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId,
 			&reviewersJSON, &pr.Status,
 		); err != nil {
-			continue
+			return nil, fmt.Errorf("ошибка при сканировании PR: %w", classify(err))
 		}
 
 		if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
-			continue
+			return nil, fmt.Errorf("ошибка при разборе ревьюверов PR %s: %w", pr.PullRequestId, err)
 		}
 
 		pullRequests = append(pullRequests, pr)
 	}
 
-	return pullRequests
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+
+	return pullRequests, nil
 }
 
+// createdKey — created_at PR для сортировки и окна дат; см. PageKey
+const createdKey = `COALESCE(pr.created_at, 'epoch'::timestamp)`
+
+func (s *Storage) ListPullRequests(ctx context.Context, filter PullRequestFilter) ([]models.PullRequest, error) {
+	cmp, order := ">", "ASC"
+	if filter.Descending {
+		cmp, order = "<", "DESC"
+	}
+
+	var status *string
+	if filter.Status != nil {
+		status = (*string)(filter.Status)
+	}
+	var afterCreated *time.Time
+	var afterId *string
+	if filter.After != nil {
+		afterCreated, afterId = &filter.After.CreatedAt, &filter.After.PullRequestId
+	}
+	var limit *int
+	if filter.Limit > 0 {
+		limit = &filter.Limit
+	}
+
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id,
+		       `+reviewersColumn+`, pr.status, pr.draft, pr.created_at, pr.merged_at, pr.closed_at
+		FROM pull_requests pr
+		WHERE ($1::text IS NULL OR EXISTS (
+		          SELECT 1 FROM pr_reviewers rv
+		          WHERE rv.pull_request_id = pr.pull_request_id AND rv.user_id = $1))
+		  AND ($2::text IS NULL OR pr.status = $2)
+		  AND ($3::timestamp IS NULL OR `+createdKey+` >= $3)
+		  AND ($4::timestamp IS NULL OR `+createdKey+` < $4)
+		  AND ($5::timestamp IS NULL OR (`+createdKey+`, pr.pull_request_id) `+cmp+` ($5, $6::text))
+		  AND ($8::text IS NULL OR pr.author_id = $8)
+		  AND ($9::text IS NULL OR EXISTS (
+		          SELECT 1 FROM users u
+		          WHERE u.user_id = pr.author_id AND u.team_name = $9))
+		  AND ($10::text IS NULL OR strpos(lower(pr.pull_request_name), lower($10)) > 0)
+		ORDER BY `+createdKey+` `+order+`, pr.pull_request_id `+order+`
+		LIMIT $7`,
+		filter.ReviewerId, status, filter.CreatedAfter, filter.CreatedBefore, afterCreated, afterId, limit,
+		filter.AuthorId, filter.TeamName, filter.NameContains,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении списка PR: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	pullRequests := []models.PullRequest{}
+	for rows.Next() {
+		var pr models.PullRequest
+		var reviewersJSON []byte
+
+		if err := rows.Scan(
+			&pr.PullRequestId, &pr.PullRequestName, &pr.AuthorId,
+			&reviewersJSON, &pr.Status, &pr.Draft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании PR: %w", classify(err))
+		}
+
+		if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+			return nil, fmt.Errorf("ошибка при разборе ревьюверов PR %s: %w", pr.PullRequestId, err)
+		}
+
+		pullRequests = append(pullRequests, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+	// Вердикты читаются после закрытия курсора: в транзакции соединение одно
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+
+	if err := s.fillReviews(ctx, pullRequests); err != nil {
+		return nil, err
+	}
+	return pullRequests, nil
+}
+
+// GetOpenReviewCounts возвращает количество открытых PR, назначенных на каждого из пользователей.
+// Пользователи без открытых ревью в результат не попадают.
+func (s *Storage) GetOpenReviewCounts(ctx context.Context, userIds []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIds))
+	if len(userIds) == 0 {
+		return counts, nil
+	}
+
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT r.user_id, COUNT(*)
+		FROM pr_reviewers r
+		JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+		WHERE pr.status = 'OPEN' AND r.user_id = ANY($1)
+		GROUP BY r.user_id`, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при подсчёте открытых ревью: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var userId string
+		var count int
+		if err := rows.Scan(&userId, &count); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании количества ревью: %w", classify(err))
+		}
+		counts[userId] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+
+	return counts, nil
+}
+
+// SaveReassignment фиксирует замену ревьювера для статистики
+func (s *Storage) SaveReassignment(ctx context.Context, prId, oldUserId, newUserId string, at time.Time) error {
+	_, err := s.q().ExecContext(ctx, `
+		INSERT INTO reviewer_reassignments (pull_request_id, old_user_id, new_user_id, reassigned_at)
+		VALUES ($1, $2, $3, $4)`,
+		prId, oldUserId, newUserId, at,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка при сохранении переназначения: %w", classify(err))
+	}
+	return nil
+}
+
+// ---------- Pull Request Events ----------
+
+func (s *Storage) AppendPullRequestEvents(ctx context.Context, events []models.PullRequestEvent) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for i := range events {
+			e := &events[i]
+			err := tx.QueryRowContext(ctx, `
+				INSERT INTO pr_events (
+					pull_request_id, event_type, actor,
+					reviewer_id, old_reviewer_id, new_reviewer_id, reason, verdict, created_at
+				)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				RETURNING id`,
+				e.PullRequestId, e.EventType, e.Actor,
+				e.ReviewerId, e.OldReviewerId, e.NewReviewerId, e.Reason, e.Verdict, e.CreatedAt,
+			).Scan(&e.EventId)
+			if err != nil {
+				return fmt.Errorf("ошибка записи события PR (pr_id=%s): %w", e.PullRequestId, classify(err))
+			}
+		}
+		return nil
+	})
+}
+
+// GetPullRequestEvents возвращает события PR в порядке записи
+func (s *Storage) GetPullRequestEvents(ctx context.Context, prId string) ([]models.PullRequestEvent, error) {
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT id, pull_request_id, event_type, actor,
+			reviewer_id, old_reviewer_id, new_reviewer_id, reason, verdict, created_at
+		FROM pr_events
+		WHERE pull_request_id=$1
+		ORDER BY id`, prId)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении событий PR: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	events := []models.PullRequestEvent{}
+	for rows.Next() {
+		var e models.PullRequestEvent
+		if err := rows.Scan(
+			&e.EventId, &e.PullRequestId, &e.EventType, &e.Actor,
+			&e.ReviewerId, &e.OldReviewerId, &e.NewReviewerId, &e.Reason, &e.Verdict, &e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании события PR: %w", classify(err))
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+	return events, nil
+}
+
+// ---------- Stats ----------
+
+// GetReviewerStats считает статистику назначений по пользователям.
+// Окно [from, to) применяется к created_at PR, для merged_reviewed — к merged_at.
+// Пустые from, to и teamName не ограничивают выборку.
+func (s *Storage) GetReviewerStats(ctx context.Context, from, to *time.Time, teamName *string) ([]models.ReviewerStats, error) {
+	rows, err := s.q().QueryContext(ctx, `
+		WITH assigned AS (
+			SELECT r.user_id,
+			       COUNT(*) FILTER (WHERE ($1::timestamp IS NULL OR pr.created_at >= $1)
+			                          AND ($2::timestamp IS NULL OR pr.created_at < $2)) AS total,
+			       COUNT(*) FILTER (WHERE pr.status = 'OPEN'
+			                          AND ($1::timestamp IS NULL OR pr.created_at >= $1)
+			                          AND ($2::timestamp IS NULL OR pr.created_at < $2)) AS open,
+			       COUNT(*) FILTER (WHERE pr.status = 'MERGED'
+			                          AND ($1::timestamp IS NULL OR pr.merged_at >= $1)
+			                          AND ($2::timestamp IS NULL OR pr.merged_at < $2)) AS merged
+			FROM pr_reviewers r
+			JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+			GROUP BY r.user_id
+		),
+		away AS (
+			SELECT ra.old_user_id AS user_id, COUNT(*) AS total
+			FROM reviewer_reassignments ra
+			JOIN pull_requests pr ON pr.pull_request_id = ra.pull_request_id
+			WHERE ($1::timestamp IS NULL OR pr.created_at >= $1)
+			  AND ($2::timestamp IS NULL OR pr.created_at < $2)
+			GROUP BY ra.old_user_id
+		)
+		SELECT u.user_id, u.username, u.team_name,
+		       COALESCE(a.total, 0) + COALESCE(w.total, 0),
+		       COALESCE(a.open, 0),
+		       COALESCE(a.merged, 0),
+		       COALESCE(w.total, 0)
+		FROM users u
+		LEFT JOIN assigned a ON a.user_id = u.user_id
+		LEFT JOIN away w ON w.user_id = u.user_id
+		WHERE ($3::text IS NULL OR u.team_name = $3)
+		ORDER BY u.team_name, u.user_id`,
+		from, to, teamName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при подсчёте статистики: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	stats := []models.ReviewerStats{}
+	for rows.Next() {
+		var st models.ReviewerStats
+		if err := rows.Scan(
+			&st.UserId, &st.Username, &st.TeamName,
+			&st.Assignments, &st.OpenAssignments, &st.MergedReviewed, &st.ReassignedAway,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании статистики: %w", classify(err))
+		}
+		stats = append(stats, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+
+	return stats, nil
+}
+
+// ---------- Webhooks ----------
+
+func (s *Storage) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription, secret string) error {
+	eventsJSON, err := json.Marshal(sub.Events)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации событий подписки: %w", err)
+	}
+
+	_, err = s.q().ExecContext(ctx, `
+		INSERT INTO webhook_subscriptions (subscription_id, url, secret, events, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		sub.SubscriptionId, sub.Url, secret, eventsJSON, sub.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения подписки: %w", classify(err))
+	}
+	return nil
+}
+
+func (s *Storage) ListWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := s.q().QueryContext(ctx, `
+		SELECT subscription_id, url, events, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at, subscription_id`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении подписок: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	subs := []models.WebhookSubscription{}
+	for rows.Next() {
+		var sub models.WebhookSubscription
+		var eventsJSON []byte
+		if err := rows.Scan(&sub.SubscriptionId, &sub.Url, &eventsJSON, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании подписки: %w", classify(err))
+		}
+		if err := json.Unmarshal(eventsJSON, &sub.Events); err != nil {
+			return nil, fmt.Errorf("ошибка десериализации событий подписки: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+	return subs, nil
+}
+
+// DeleteWebhookSubscription удаляет подписку вместе с недоставленными событиями
+// или возвращает ErrNotFound, если подписки нет
+func (s *Storage) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	res, err := s.q().ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE subscription_id=$1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления подписки: %w", classify(err))
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка удаления подписки: %w", classify(err))
+	}
+	if affected == 0 {
+		return fmt.Errorf("подписка %s: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+func (s *Storage) EnqueueWebhook(ctx context.Context, event models.WebhookEventType, payload []byte, at time.Time) error {
+	_, err := s.q().ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, payload, next_attempt_at, created_at)
+		SELECT subscription_id, $1, $2, $3, $3
+		FROM webhook_subscriptions
+		WHERE jsonb_array_length(events) = 0 OR jsonb_exists(events, $1)`,
+		event, payload, at,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка постановки вебхука в очередь: %w", classify(err))
+	}
+	return nil
+}
+
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]WebhookDelivery, error) {
+	// SKIP LOCKED позволяет нескольким экземплярам сервиса разбирать outbox параллельно
+	rows, err := s.q().QueryContext(ctx, `
+		WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d
+			SET attempts = d.attempts + 1, next_attempt_at = $2
+			FROM due
+			WHERE d.id = due.id
+			RETURNING d.id, d.subscription_id, d.event_type, d.payload, d.attempts
+		)
+		SELECT c.id, c.subscription_id, s.url, s.secret, c.event_type, c.payload, c.attempts
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.subscription_id = c.subscription_id
+		ORDER BY c.id`,
+		now, leaseUntil, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при выборке вебхуков: %w", classify(err))
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		if err := rows.Scan(&d.Id, &d.SubscriptionId, &d.Url, &d.Secret, &d.Event, &d.Payload, &d.Attempt); err != nil {
+			return nil, fmt.Errorf("ошибка при сканировании вебхука: %w", classify(err))
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", classify(err))
+	}
+	return deliveries, nil
+}
+
+func (s *Storage) CompleteWebhookDelivery(ctx context.Context, id int64, at time.Time) error {
+	_, err := s.q().ExecContext(ctx, `
+		UPDATE webhook_deliveries SET delivered_at=$2, last_error=NULL WHERE id=$1`, id, at)
+	if err != nil {
+		return fmt.Errorf("ошибка при отметке доставки вебхука: %w", classify(err))
+	}
+	return nil
+}
+
+func (s *Storage) RetryWebhookDelivery(ctx context.Context, id int64, lastErr string, next time.Time) error {
+	_, err := s.q().ExecContext(ctx, `
+		UPDATE webhook_deliveries SET last_error=$2, next_attempt_at=$3 WHERE id=$1`, id, lastErr, next)
+	if err != nil {
+		return fmt.Errorf("ошибка при переносе доставки вебхука: %w", classify(err))
+	}
+	return nil
+}
+
+func (s *Storage) FailWebhookDelivery(ctx context.Context, id int64, lastErr string, at time.Time) error {
+	_, err := s.q().ExecContext(ctx, `
+		UPDATE webhook_deliveries SET last_error=$2, failed_at=$3 WHERE id=$1`, id, lastErr, at)
+	if err != nil {
+		return fmt.Errorf("ошибка при отметке недоставленного вебхука: %w", classify(err))
+	}
+	return nil
+}
+
+// ---------- Health ----------
+
+func (s *Storage) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ошибка проверки соединения с БД: %w", classify(err))
+	}
+	return nil
+}
+
+// MigrationVersion читает версию схемы из таблицы goose: для каждой версии берётся
+// последняя запись, откаченные версии (is_applied = false) не учитываются
+func (s *Storage) MigrationVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := s.q().QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(version_id), 0)
+		FROM (
+			SELECT DISTINCT ON (version_id) version_id, is_applied
+			FROM goose_db_version
+			ORDER BY version_id, id DESC
+		) v
+		WHERE is_applied`).Scan(&version)
+
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения версии миграций: %w", classify(err))
+	}
+	return version, nil
+}
@@ -0,0 +1,122 @@
+// Package github переводит вебхуки GitHub pull_request в вызовы сервиса
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pr-reviewer/internal/integrations"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"pr-reviewer/internal/webhook"
+)
+
+// Заголовки вебхука GitHub
+const (
+	EventHeader     = "X-GitHub-Event"
+	SignatureHeader = "X-Hub-Signature-256"
+)
+
+// Service — методы сервиса, которыми пользуется адаптер
+type Service interface {
+	integrations.Service
+	ResolveGitHubUser(ctx context.Context, login string) (string, error)
+}
+
+// Adapter обрабатывает события pull_request
+type Adapter struct {
+	service Service
+	secret  string
+}
+
+// NewAdapter создаёт адаптер; secret — секрет вебхука, заданный в настройках репозитория GitHub
+func NewAdapter(svc Service, secret string) *Adapter {
+	return &Adapter{service: svc, secret: secret}
+}
+
+// VerifySignature сравнивает значение X-Hub-Signature-256 с HMAC-SHA256 тела
+func (a *Adapter) VerifySignature(body []byte, signature string) bool {
+	// GitHub подписывает тело так же, как исходящие вебхуки сервиса
+	return signature != "" && hmac.Equal([]byte(signature), []byte(webhook.Sign(a.secret, body)))
+}
+
+// pullRequestEvent — используемые поля события pull_request
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string  `json:"title"`
+		Merged bool    `json:"merged"`
+		User   account `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender account `json:"sender"`
+}
+
+type account struct {
+	Login string `json:"login"`
+}
+
+// Handle обрабатывает событие event (значение X-GitHub-Event) с телом body.
+// opened создаёт PR, reopened открывает закрытый PR заново (или создаёт, если его ещё нет),
+// closed объединяет PR при merged=true и закрывает его иначе, остальное пропускается.
+func (a *Adapter) Handle(ctx context.Context, event string, body []byte) (*models.IntegrationResult, error) {
+	if event != "pull_request" {
+		return integrations.Result("", integrations.ResultIgnored, nil), nil
+	}
+
+	var e pullRequestEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", integrations.ErrInvalidPayload, err)
+	}
+	if e.Repository.FullName == "" || e.Number == 0 {
+		return nil, fmt.Errorf("%w: нет repository.full_name или number", integrations.ErrInvalidPayload)
+	}
+
+	prId := fmt.Sprintf("%s#%d", e.Repository.FullName, e.Number)
+	actor, err := a.actor(ctx, e.Sender.Login)
+	if err != nil {
+		return nil, err
+	}
+	ctx = service.WithActor(ctx, actor)
+
+	switch e.Action {
+	case "opened":
+		return a.open(ctx, e, prId)
+	case "reopened":
+		result, err := integrations.Reopen(ctx, a.service, e.Action, prId)
+		if errors.Is(err, service.ErrPRNotFound) {
+			return a.open(ctx, e, prId)
+		}
+		return result, err
+	case "closed":
+		if !e.PullRequest.Merged {
+			return integrations.Close(ctx, a.service, e.Action, prId)
+		}
+		return integrations.Merge(ctx, a.service, e.Action, prId)
+	default:
+		return integrations.Result(e.Action, integrations.ResultIgnored, nil), nil
+	}
+}
+
+// open создаёт PR от имени автора pull request
+func (a *Adapter) open(ctx context.Context, e pullRequestEvent, prId string) (*models.IntegrationResult, error) {
+	authorId, err := a.service.ResolveGitHubUser(ctx, e.PullRequest.User.Login)
+	if err != nil {
+		return nil, err
+	}
+	return integrations.Open(ctx, a.service, e.Action, prId, e.PullRequest.Title, authorId)
+}
+
+// actor возвращает user_id отправителя события или его логин с префиксом github:, если он не сопоставлен
+func (a *Adapter) actor(ctx context.Context, login string) (string, error) {
+	userId, err := a.service.ResolveGitHubUser(ctx, login)
+	if errors.Is(err, service.ErrGitHubUserNotMapped) {
+		return "github:" + login, nil
+	}
+	return userId, err
+}
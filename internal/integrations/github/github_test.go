@@ -0,0 +1,116 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/integrations"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"pr-reviewer/internal/webhook"
+	"testing"
+)
+
+func newTestAdapter(t *testing.T) (*Adapter, *service.Service) {
+	t.Helper()
+	ctx := context.Background()
+
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+	err := svc.CreateTeam(ctx, &models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "u1", Username: "alice", IsActive: true},
+		{UserId: "u2", Username: "bob", IsActive: true},
+		{UserId: "u3", Username: "carol", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	if err := svc.SetGitHubUser(ctx, "Alice-GH", "u1"); err != nil {
+		t.Fatalf("Ошибка сопоставления логина: %v", err)
+	}
+	return NewAdapter(svc, "s3cr3t"), svc
+}
+
+func event(action, author, sender string, merged bool) []byte {
+	return []byte(fmt.Sprintf(`{
+		"action": %q,
+		"number": 42,
+		"pull_request": {"title": "Add feature", "merged": %t, "user": {"login": %q}},
+		"repository": {"full_name": "octo/repo"},
+		"sender": {"login": %q}
+	}`, action, merged, author, sender))
+}
+
+func TestVerifySignature(t *testing.T) {
+	a, _ := newTestAdapter(t)
+	body := event("opened", "alice-gh", "alice-gh", false)
+
+	if !a.VerifySignature(body, webhook.Sign("s3cr3t", body)) {
+		t.Fatal("Верная подпись отклонена")
+	}
+	for _, signature := range []string{"", webhook.Sign("other", body), "sha256=00"} {
+		if a.VerifySignature(body, signature) {
+			t.Fatalf("Подпись %q должна быть отклонена", signature)
+		}
+	}
+}
+
+func TestHandleIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	a, svc := newTestAdapter(t)
+
+	steps := []struct {
+		event string
+		body  []byte
+		want  string
+	}{
+		{"ping", []byte(`{"zen": "Keep it logically awesome."}`), integrations.ResultIgnored},
+		{"pull_request", event("opened", "ALICE-GH", "stranger", false), integrations.ResultCreated},
+		{"pull_request", event("opened", "alice-gh", "stranger", false), integrations.ResultUnchanged},
+		{"pull_request", event("reopened", "alice-gh", "alice-gh", false), integrations.ResultUnchanged},
+		{"pull_request", event("labeled", "alice-gh", "alice-gh", false), integrations.ResultIgnored},
+		{"pull_request", event("closed", "alice-gh", "alice-gh", false), integrations.ResultClosed},
+		{"pull_request", event("closed", "alice-gh", "alice-gh", false), integrations.ResultUnchanged},
+		{"pull_request", event("reopened", "alice-gh", "stranger", false), integrations.ResultReopened},
+		{"pull_request", event("closed", "alice-gh", "alice-gh", true), integrations.ResultMerged},
+		{"pull_request", event("closed", "alice-gh", "alice-gh", true), integrations.ResultUnchanged},
+	}
+	for _, step := range steps {
+		result, err := a.Handle(ctx, step.event, step.body)
+		if err != nil {
+			t.Fatalf("%s: ошибка обработки: %v", step.body, err)
+		}
+		if result.Result != step.want {
+			t.Fatalf("%s: ожидался результат %s, получен %s", step.body, step.want, result.Result)
+		}
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "octo/repo#42")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+	// Несопоставленный отправитель записывается логином GitHub, сопоставленный — user_id
+	if events[0].Actor != "github:stranger" || events[len(events)-1].Actor != "u1" {
+		t.Fatalf("Неверные инициаторы в журнале: %+v", events)
+	}
+}
+
+func TestHandleErrors(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestAdapter(t)
+
+	// reopened для неизвестного PR создаёт его, поэтому автор тоже должен быть сопоставлен
+	for _, action := range []string{"opened", "reopened"} {
+		if _, err := a.Handle(ctx, "pull_request", event(action, "unknown", "unknown", false)); !errors.Is(err, service.ErrGitHubUserNotMapped) {
+			t.Fatalf("%s: ожидалась ошибка ErrGitHubUserNotMapped, получена %v", action, err)
+		}
+	}
+	if _, err := a.Handle(ctx, "pull_request", []byte(`{"action": "opened"}`)); !errors.Is(err, integrations.ErrInvalidPayload) {
+		t.Fatalf("Ожидалась ошибка ErrInvalidPayload, получена %v", err)
+	}
+	// merge PR, созданного до подключения интеграции, пропускается
+	result, err := a.Handle(ctx, "pull_request", event("closed", "alice-gh", "alice-gh", true))
+	if err != nil || result.Result != integrations.ResultIgnored {
+		t.Fatalf("Ожидался результат ignored, получено %+v (%v)", result, err)
+	}
+}
@@ -0,0 +1,100 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultClientTimeout ограничивает каждый запрос к API GitLab, если http.Client не передан
+const DefaultClientTimeout = 10 * time.Second
+
+// APIClient назначает ревьюверов через REST API v4 GitLab
+type APIClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewAPIClient создаёт клиент; baseURL — адрес инстанса GitLab (например, https://gitlab.com),
+// token — персональный или проектный токен доступа с правом api. client может быть nil.
+func NewAPIClient(baseURL, token string, client *http.Client) *APIClient {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultClientTimeout}
+	}
+	return &APIClient{baseURL: strings.TrimRight(baseURL, "/"), token: token, client: client}
+}
+
+// SetReviewers заменяет ревьюверов merge request пользователями GitLab с данными username
+func (c *APIClient) SetReviewers(ctx context.Context, projectId, mergeRequestIid int64, usernames []string) error {
+	reviewerIds := make([]int64, 0, len(usernames))
+	for _, username := range usernames {
+		id, err := c.userId(ctx, username)
+		if err != nil {
+			return err
+		}
+		reviewerIds = append(reviewerIds, id)
+	}
+
+	body, err := json.Marshal(map[string][]int64{"reviewer_ids": reviewerIds})
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/api/v4/projects/%d/merge_requests/%d", projectId, mergeRequestIid)
+	return c.do(ctx, http.MethodPut, path, body, nil)
+}
+
+// userId находит числовой идентификатор пользователя GitLab по username
+func (c *APIClient) userId(ctx context.Context, username string) (int64, error) {
+	var users []struct {
+		Id int64 `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v4/users?username="+url.QueryEscape(username), nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("пользователь GitLab %s не найден", username)
+	}
+	return users[0].Id, nil
+}
+
+func (c *APIClient) do(ctx context.Context, method, path string, body []byte, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("некорректный запрос: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Дочитываем ответ, чтобы соединение вернулось в пул
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("GitLab ответил %d на %s %s", resp.StatusCode, method, path)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("некорректный ответ GitLab: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Package gitlab переводит вебхуки GitLab Merge Request Hook в вызовы сервиса
+// и при необходимости записывает назначенных ревьюверов обратно в merge request
+package gitlab
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pr-reviewer/internal/integrations"
+	"pr-reviewer/internal/logging"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"sync"
+	"time"
+)
+
+// Заголовки вебхука GitLab
+const (
+	EventHeader = "X-Gitlab-Event"
+	TokenHeader = "X-Gitlab-Token"
+)
+
+const mergeRequestHook = "Merge Request Hook"
+
+// DefaultWriteBackTimeout ограничивает фоновую запись ревьюверов одного MR в GitLab
+const DefaultWriteBackTimeout = 30 * time.Second
+
+// Service — методы сервиса, которыми пользуется адаптер
+type Service interface {
+	integrations.Service
+	ResolveUsername(ctx context.Context, username string) (string, error)
+	GetUsernames(ctx context.Context, userIds []string) ([]string, error)
+}
+
+// ReviewerClient назначает ревьюверов merge request в GitLab
+type ReviewerClient interface {
+	SetReviewers(ctx context.Context, projectId, mergeRequestIid int64, usernames []string) error
+}
+
+// Options — необязательные параметры адаптера
+type Options struct {
+	// Reviewers записывает назначенных ревьюверов в созданный MR; nil отключает запись
+	Reviewers ReviewerClient
+	// WriteBackTimeout ограничивает запись ревьюверов одного MR; 0 — DefaultWriteBackTimeout
+	WriteBackTimeout time.Duration
+	Logger           *slog.Logger
+}
+
+// Adapter обрабатывает события Merge Request Hook
+type Adapter struct {
+	service          Service
+	token            string
+	reviewers        ReviewerClient
+	writeBackTimeout time.Duration
+	logger           *slog.Logger
+	// writeBacks отслеживает фоновые записи ревьюверов для Wait
+	writeBacks sync.WaitGroup
+}
+
+// NewAdapter создаёт адаптер; token — секретный токен, заданный в настройках вебхука GitLab
+func NewAdapter(svc Service, token string, opts Options) *Adapter {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.WriteBackTimeout <= 0 {
+		opts.WriteBackTimeout = DefaultWriteBackTimeout
+	}
+	return &Adapter{
+		service:          svc,
+		token:            token,
+		reviewers:        opts.Reviewers,
+		writeBackTimeout: opts.WriteBackTimeout,
+		logger:           opts.Logger,
+	}
+}
+
+// Wait дожидается завершения фоновой записи ревьюверов в GitLab
+func (a *Adapter) Wait() {
+	a.writeBacks.Wait()
+}
+
+// VerifyToken сравнивает значение X-Gitlab-Token с настроенным токеном за постоянное время
+func (a *Adapter) VerifyToken(token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1
+}
+
+// mergeRequestEvent — используемые поля события merge_request
+type mergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Id       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		Id                int64  `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		Iid      int64  `json:"iid"`
+		AuthorId int64  `json:"author_id"`
+		Title    string `json:"title"`
+		State    string `json:"state"`
+		Action   string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// Handle обрабатывает событие event (значение X-Gitlab-Event) с телом body.
+// open и update открытого MR создают PR, reopen открывает закрытый PR заново (или создаёт его),
+// merge объединяет PR, close закрывает его, остальное пропускается.
+// Событие MR содержит только числовой author_id, поэтому PR создаётся, лишь когда событие
+// вызвал сам автор MR (для open это так всегда); иначе создание пропускается.
+func (a *Adapter) Handle(ctx context.Context, event string, body []byte) (*models.IntegrationResult, error) {
+	if event != mergeRequestHook {
+		return integrations.Result("", integrations.ResultIgnored, nil), nil
+	}
+
+	var e mergeRequestEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", integrations.ErrInvalidPayload, err)
+	}
+	if e.ObjectKind != "merge_request" || e.Project.PathWithNamespace == "" || e.ObjectAttributes.Iid == 0 {
+		return nil, fmt.Errorf("%w: нет project.path_with_namespace или object_attributes.iid", integrations.ErrInvalidPayload)
+	}
+
+	attrs := e.ObjectAttributes
+	prId := fmt.Sprintf("%s!%d", e.Project.PathWithNamespace, attrs.Iid)
+	actor, err := a.actor(ctx, e.User.Username)
+	if err != nil {
+		return nil, err
+	}
+	ctx = service.WithActor(ctx, actor)
+
+	switch {
+	case attrs.Action == "open" || (attrs.Action == "update" && attrs.State == "opened"):
+		return a.open(ctx, e, prId)
+	case attrs.Action == "reopen":
+		result, err := integrations.Reopen(ctx, a.service, attrs.Action, prId)
+		if errors.Is(err, service.ErrPRNotFound) {
+			return a.open(ctx, e, prId)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Пока PR был закрыт, неактивные ревьюверы могли смениться
+		if result.Result == integrations.ResultReopened {
+			a.writeBack(ctx, e.Project.Id, attrs.Iid, result.PullRequest)
+		}
+		return result, nil
+	case attrs.Action == "close":
+		return integrations.Close(ctx, a.service, attrs.Action, prId)
+	case attrs.Action == "merge":
+		return integrations.Merge(ctx, a.service, attrs.Action, prId)
+	default:
+		return integrations.Result(attrs.Action, integrations.ResultIgnored, nil), nil
+	}
+}
+
+// open создаёт PR, если событие вызвал автор MR, и записывает назначенных ревьюверов в GitLab
+func (a *Adapter) open(ctx context.Context, e mergeRequestEvent, prId string) (*models.IntegrationResult, error) {
+	attrs := e.ObjectAttributes
+	if e.User.Id != attrs.AuthorId {
+		return integrations.Result(attrs.Action, integrations.ResultIgnored, nil), nil
+	}
+	authorId, err := a.service.ResolveUsername(ctx, e.User.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := integrations.Open(ctx, a.service, attrs.Action, prId, attrs.Title, authorId)
+	if err != nil {
+		return nil, err
+	}
+	if result.Result == integrations.ResultCreated {
+		a.writeBack(ctx, e.Project.Id, attrs.Iid, result.PullRequest)
+	}
+	return result, nil
+}
+
+// writeBack назначает ревьюверов PR в merge request. Запросы к GitLab выполняются в фоне
+// со своим таймаутом, чтобы медленный GitLab не задерживал ответ на вебхук.
+// PR уже создан, поэтому ошибка только логируется: повтор вебхука вернул бы unchanged и не исправил бы её.
+func (a *Adapter) writeBack(ctx context.Context, projectId, iid int64, pr *models.PullRequest) {
+	if a.reviewers == nil || len(pr.AssignedReviewers) == 0 {
+		return
+	}
+
+	usernames, err := a.service.GetUsernames(ctx, pr.AssignedReviewers)
+	if err != nil {
+		a.logWriteBackError(ctx, pr.PullRequestId, err)
+		return
+	}
+
+	// Запись переживает запрос вебхука, но сохраняет значения его контекста для логов
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.writeBackTimeout)
+	a.writeBacks.Add(1)
+	go func() {
+		defer a.writeBacks.Done()
+		defer cancel()
+		if err := a.reviewers.SetReviewers(ctx, projectId, iid, usernames); err != nil {
+			a.logWriteBackError(ctx, pr.PullRequestId, err)
+		}
+	}()
+}
+
+func (a *Adapter) logWriteBackError(ctx context.Context, prId string, err error) {
+	a.logger.WarnContext(ctx, "Не удалось назначить ревьюверов в GitLab",
+		slog.String("pull_request_id", prId), logging.Err(err))
+}
+
+// actor возвращает user_id пользователя, вызвавшего событие, или его username с префиксом gitlab:
+func (a *Adapter) actor(ctx context.Context, username string) (string, error) {
+	userId, err := a.service.ResolveUsername(ctx, username)
+	if errors.Is(err, service.ErrUsernameNotFound) || errors.Is(err, service.ErrUsernameAmbiguous) {
+		return "gitlab:" + username, nil
+	}
+	return userId, err
+}
@@ -0,0 +1,266 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/integrations"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeReviewers запоминает вызовы SetReviewers
+type fakeReviewers struct {
+	mu    sync.Mutex
+	calls [][]string
+	err   error
+}
+
+func (f *fakeReviewers) SetReviewers(_ context.Context, projectId, iid int64, usernames []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, append([]string{fmt.Sprintf("%d!%d", projectId, iid)}, usernames...))
+	return f.err
+}
+
+// slowReviewers ждёт отмены контекста, как GitLab, который не отвечает
+type slowReviewers struct {
+	err chan error
+}
+
+func (s *slowReviewers) SetReviewers(ctx context.Context, _, _ int64, _ []string) error {
+	<-ctx.Done()
+	s.err <- ctx.Err()
+	return ctx.Err()
+}
+
+func newTestAdapter(t *testing.T, reviewers ReviewerClient) (*Adapter, *service.Service) {
+	t.Helper()
+	ctx := context.Background()
+
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{ReviewerCount: 1})
+	err := svc.CreateTeam(ctx, &models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "u1", Username: "alice", IsActive: true},
+		{UserId: "u2", Username: "bob", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	err = svc.CreateTeam(ctx, &models.Team{TeamName: "frontend", Members: []models.TeamMember{
+		{UserId: "u3", Username: "dave", IsActive: true},
+		{UserId: "u4", Username: "Dave", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	return NewAdapter(svc, "t0ken", Options{Reviewers: reviewers}), svc
+}
+
+// event собирает событие MR; author — вызвал ли событие автор MR
+func event(action, state, username string, author bool) []byte {
+	userId := 2
+	if author {
+		userId = 1
+	}
+	return []byte(fmt.Sprintf(`{
+		"object_kind": "merge_request",
+		"user": {"id": %d, "username": %q},
+		"project": {"id": 7, "path_with_namespace": "group/repo"},
+		"object_attributes": {"iid": 42, "author_id": 1, "title": "Add feature", "state": %q, "action": %q}
+	}`, userId, username, state, action))
+}
+
+func TestVerifyToken(t *testing.T) {
+	a, _ := newTestAdapter(t, nil)
+
+	if !a.VerifyToken("t0ken") {
+		t.Fatal("Верный токен отклонён")
+	}
+	for _, token := range []string{"", "T0KEN", "t0ken "} {
+		if a.VerifyToken(token) {
+			t.Fatalf("Токен %q должен быть отклонён", token)
+		}
+	}
+}
+
+func TestHandleIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	reviewers := &fakeReviewers{}
+	a, svc := newTestAdapter(t, reviewers)
+
+	steps := []struct {
+		event string
+		body  []byte
+		want  string
+	}{
+		{"Push Hook", []byte(`{"object_kind": "push"}`), integrations.ResultIgnored},
+		// PR ещё нет, а автор события неизвестен — создавать нечего
+		{mergeRequestHook, event("reopen", "opened", "stranger", false), integrations.ResultIgnored},
+		{mergeRequestHook, event("update", "opened", "ALICE", true), integrations.ResultCreated},
+		{mergeRequestHook, event("open", "opened", "alice", true), integrations.ResultUnchanged},
+		{mergeRequestHook, event("approved", "opened", "bob", false), integrations.ResultIgnored},
+		{mergeRequestHook, event("close", "closed", "alice", true), integrations.ResultClosed},
+		{mergeRequestHook, event("close", "closed", "alice", true), integrations.ResultUnchanged},
+		{mergeRequestHook, event("update", "closed", "alice", true), integrations.ResultIgnored},
+		{mergeRequestHook, event("reopen", "opened", "stranger", false), integrations.ResultReopened},
+		{mergeRequestHook, event("reopen", "opened", "alice", true), integrations.ResultUnchanged},
+		{mergeRequestHook, event("merge", "merged", "bob", false), integrations.ResultMerged},
+		{mergeRequestHook, event("merge", "merged", "bob", false), integrations.ResultUnchanged},
+	}
+	for _, step := range steps {
+		result, err := a.Handle(ctx, step.event, step.body)
+		if err != nil {
+			t.Fatalf("%s: ошибка обработки: %v", step.body, err)
+		}
+		if result.Result != step.want {
+			t.Fatalf("%s: ожидался результат %s, получен %s", step.body, step.want, result.Result)
+		}
+	}
+
+	// Ревьюверы записываются в GitLab при создании PR и при повторном открытии
+	a.Wait()
+	if want := [][]string{{"7!42", "bob"}, {"7!42", "bob"}}; !slices.EqualFunc(reviewers.calls, want, slices.Equal) {
+		t.Fatalf("Ожидались вызовы %v, получены %v", want, reviewers.calls)
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "group/repo!42")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+	if events[0].Actor != "u1" || events[len(events)-1].Actor != "u2" {
+		t.Fatalf("Неверные инициаторы в журнале: %+v", events)
+	}
+}
+
+func TestHandleMergeSkipsApprovalGate(t *testing.T) {
+	ctx := context.Background()
+	a, svc := newTestAdapter(t, nil)
+
+	approvals := 1
+	_, err := svc.SetTeamSettings(ctx, &models.TeamSettings{
+		TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+
+	// MR объединён в GitLab без одобрений в сервисе: merge всё равно фиксируется
+	for _, step := range []struct {
+		body []byte
+		want string
+	}{
+		{event("open", "opened", "alice", true), integrations.ResultCreated},
+		{event("merge", "merged", "bob", false), integrations.ResultMerged},
+	} {
+		result, err := a.Handle(ctx, mergeRequestHook, step.body)
+		if err != nil || result.Result != step.want {
+			t.Fatalf("%s: ожидался результат %s, получено %+v (%v)", step.body, step.want, result, err)
+		}
+	}
+}
+
+func TestHandleErrors(t *testing.T) {
+	ctx := context.Background()
+	reviewers := &fakeReviewers{err: errors.New("GitLab недоступен")}
+	a, _ := newTestAdapter(t, reviewers)
+
+	if _, err := a.Handle(ctx, mergeRequestHook, event("open", "opened", "stranger", true)); !errors.Is(err, service.ErrUsernameNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrUsernameNotFound, получена %v", err)
+	}
+	// username совпадает у двух пользователей без учёта регистра
+	if _, err := a.Handle(ctx, mergeRequestHook, event("open", "opened", "dave", true)); !errors.Is(err, service.ErrUsernameAmbiguous) {
+		t.Fatalf("Ожидалась ошибка ErrUsernameAmbiguous, получена %v", err)
+	}
+	if _, err := a.Handle(ctx, mergeRequestHook, []byte(`{"object_kind": "merge_request"}`)); !errors.Is(err, integrations.ErrInvalidPayload) {
+		t.Fatalf("Ожидалась ошибка ErrInvalidPayload, получена %v", err)
+	}
+	// merge и close MR, созданного до подключения интеграции, пропускаются
+	for _, action := range []string{"merge", "close"} {
+		result, err := a.Handle(ctx, mergeRequestHook, event(action, "closed", "alice", true))
+		if err != nil || result.Result != integrations.ResultIgnored {
+			t.Fatalf("%s: ожидался результат ignored, получено %+v (%v)", action, result, err)
+		}
+	}
+	// Ошибка записи ревьюверов не отменяет созданный PR
+	result, err := a.Handle(ctx, mergeRequestHook, event("open", "opened", "alice", true))
+	a.Wait()
+	if err != nil || result.Result != integrations.ResultCreated || len(reviewers.calls) != 1 {
+		t.Fatalf("Ожидался результат created, получено %+v (%v)", result, err)
+	}
+}
+
+func TestWriteBackDoesNotBlockWebhook(t *testing.T) {
+	reviewers := &slowReviewers{err: make(chan error, 1)}
+	a, _ := newTestAdapter(t, nil)
+	a.reviewers, a.writeBackTimeout = reviewers, 50*time.Millisecond
+
+	// Контекст вебхука отменяется сразу после ответа, а запись ревьюверов продолжается
+	ctx, cancel := context.WithCancel(context.Background())
+	result, err := a.Handle(ctx, mergeRequestHook, event("open", "opened", "alice", true))
+	cancel()
+	if err != nil || result.Result != integrations.ResultCreated {
+		t.Fatalf("Ожидался результат created, получено %+v (%v)", result, err)
+	}
+
+	select {
+	case err := <-reviewers.err:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Запись должна прерываться своим таймаутом, получена ошибка %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Запись ревьюверов не прервана по таймауту")
+	}
+	a.Wait()
+}
+
+func TestAPIClientSetReviewers(t *testing.T) {
+	var updated map[string][]int64
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "api-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/users":
+			ids := map[string]int{"bob": 11, "carol": 12}
+			if id, ok := ids[r.URL.Query().Get("username")]; ok {
+				fmt.Fprintf(w, `[{"id": %d}]`, id)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v4/projects/7/merge_requests/42":
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gitlab.Close()
+	ctx := context.Background()
+
+	client := NewAPIClient(gitlab.URL+"/", "api-token", nil)
+	if err := client.SetReviewers(ctx, 7, 42, []string{"bob", "carol"}); err != nil {
+		t.Fatalf("Ошибка назначения ревьюверов: %v", err)
+	}
+	if !slices.Equal(updated["reviewer_ids"], []int64{11, 12}) {
+		t.Fatalf("Неверное тело запроса: %v", updated)
+	}
+
+	if err := client.SetReviewers(ctx, 7, 42, []string{"ghost"}); err == nil {
+		t.Fatal("Ожидалась ошибка для неизвестного пользователя")
+	}
+	if err := NewAPIClient(gitlab.URL, "wrong", nil).SetReviewers(ctx, 7, 42, []string{"bob"}); err == nil {
+		t.Fatal("Ожидалась ошибка для неверного токена")
+	}
+}
@@ -0,0 +1,111 @@
+// Package integrations содержит общее для адаптеров входящих вебхуков систем контроля версий:
+// идемпотентные создание, merge, закрытие и повторное открытие PR и коды результата обработки события.
+package integrations
+
+import (
+	"context"
+	"errors"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"time"
+)
+
+// Значения поля result в IntegrationResult
+const (
+	ResultCreated   = "created"
+	ResultMerged    = "merged"
+	ResultClosed    = "closed"
+	ResultReopened  = "reopened"
+	ResultUnchanged = "unchanged"
+	ResultIgnored   = "ignored"
+)
+
+// ErrInvalidPayload — тело события не разобрано или в нём нет обязательных полей
+var ErrInvalidPayload = errors.New("некорректное тело события")
+
+// Service — методы сервиса, которыми пользуются адаптеры
+type Service interface {
+	CreatePullRequest(ctx context.Context, prId, prName, authorId string) (*models.PullRequest, error)
+	RecordExternalMerge(ctx context.Context, prId string) (*models.PullRequest, error)
+	ClosePullRequest(ctx context.Context, prId string) (*models.PullRequest, error)
+	ReopenPullRequest(ctx context.Context, prId string) (*models.PullRequest, error)
+	GetPullRequest(ctx context.Context, prId string) (*models.PullRequest, error)
+}
+
+// Open создаёт PR; если он уже существует, событие считается обработанным ранее
+func Open(ctx context.Context, svc Service, action, prId, prName, authorId string) (*models.IntegrationResult, error) {
+	pr, err := svc.CreatePullRequest(ctx, prId, prName, authorId)
+	if errors.Is(err, service.ErrPRExists) {
+		return Result(action, ResultUnchanged, nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Result(action, ResultCreated, pr), nil
+}
+
+// Merge фиксирует внешний merge PR без проверки одобрений. PR, созданный до подключения интеграции, пропускается.
+func Merge(ctx context.Context, svc Service, action, prId string) (*models.IntegrationResult, error) {
+	start := time.Now()
+	pr, err := svc.RecordExternalMerge(ctx, prId)
+	if errors.Is(err, service.ErrPRNotFound) {
+		return Result(action, ResultIgnored, nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// RecordExternalMerge идемпотентен; объединённый раньше PR сохраняет прежний mergedAt
+	if pr.MergedAt != nil && pr.MergedAt.Before(start) {
+		return Result(action, ResultUnchanged, pr), nil
+	}
+	return Result(action, ResultMerged, pr), nil
+}
+
+// Close закрывает PR без merge. Неизвестный и уже объединённый PR пропускаются.
+func Close(ctx context.Context, svc Service, action, prId string) (*models.IntegrationResult, error) {
+	start := time.Now()
+	pr, err := svc.ClosePullRequest(ctx, prId)
+	if errors.Is(err, service.ErrPRNotFound) || errors.Is(err, service.ErrPRAlreadyMerged) {
+		return Result(action, ResultIgnored, nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Повторное закрытие сохраняет прежний closedAt
+	if pr.ClosedAt != nil && pr.ClosedAt.Before(start) {
+		return Result(action, ResultUnchanged, pr), nil
+	}
+	return Result(action, ResultClosed, pr), nil
+}
+
+// Reopen открывает закрытый PR заново. Для PR, которого ещё нет, возвращает service.ErrPRNotFound,
+// чтобы адаптер мог создать его через Open; объединённый PR пропускается.
+func Reopen(ctx context.Context, svc Service, action, prId string) (*models.IntegrationResult, error) {
+	pr, err := svc.GetPullRequest(ctx, prId)
+	if err != nil {
+		return nil, err
+	}
+	switch pr.Status {
+	case models.PullRequestStatusOPEN:
+		return Result(action, ResultUnchanged, pr), nil
+	case models.PullRequestStatusMERGED:
+		return Result(action, ResultIgnored, nil), nil
+	}
+
+	pr, err = svc.ReopenPullRequest(ctx, prId)
+	if err != nil {
+		return nil, err
+	}
+	return Result(action, ResultReopened, pr), nil
+}
+
+// Result собирает ответ адаптера
+func Result(action, result string, pr *models.PullRequest) *models.IntegrationResult {
+	r := &models.IntegrationResult{Result: result, PullRequest: pr}
+	if action != "" {
+		r.Action = &action
+	}
+	return r
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerAddsRequestIDAndErrorChain(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errUnavailable := errors.New("БД недоступна")
+	cause := errors.New("connection refused")
+	err = fmt.Errorf("ошибка при получении команды: %w", fmt.Errorf("%w: %w", errUnavailable, cause))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.ErrorContext(ctx, "Внутренняя ошибка", Err(err))
+
+	var entry struct {
+		RequestID string `json:"request_id"`
+		Error     struct {
+			Message string   `json:"message"`
+			Chain   []string `json:"chain"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Ошибка разбора записи лога %q: %v", buf.String(), err)
+	}
+
+	if entry.RequestID != "req-1" {
+		t.Fatalf("Ожидался request_id req-1, получен %q", entry.RequestID)
+	}
+	if entry.Error.Message != err.Error() {
+		t.Fatalf("Неверное сообщение ошибки: %q", entry.Error.Message)
+	}
+	if len(entry.Error.Chain) != 4 || !strings.HasSuffix(entry.Error.Chain[3], "connection refused") {
+		t.Fatalf("Цепочка должна содержать обе причины: %v", entry.Error.Chain)
+	}
+}
+
+func TestNewRejectsUnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, slog.LevelInfo, "xml"); err == nil {
+		t.Fatal("Ожидалась ошибка для неизвестного формата")
+	}
+}
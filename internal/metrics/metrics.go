@@ -0,0 +1,201 @@
+// Package metrics собирает метрики сервиса в формате Prometheus:
+// HTTP-запросы по маршрутам ServerInterface, запросы к хранилищу, пул соединений и доменные события.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pr_reviewer"
+
+// Metrics хранит собственный реестр, чтобы тесты и несколько экземпляров не конфликтовали
+// с глобальным prometheus.DefaultRegisterer
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	queryDuration *prometheus.HistogramVec
+
+	prCreated      prometheus.Counter
+	prReady        prometheus.Counter
+	prMerged       prometheus.Counter
+	reassignments  prometheus.Counter
+	noCandidate    prometheus.Counter
+	prUnderstaffed prometheus.Counter
+}
+
+// New создаёт метрики и регистрирует их вместе с метриками рантайма Go и процесса
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Количество HTTP-запросов по маршрутам API.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Время обработки HTTP-запросов по маршрутам API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Время выполнения операций хранилища.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		prCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Количество созданных PR, включая черновики.",
+		}),
+		prReady: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_ready_total",
+			Help:      "Количество черновиков, помеченных готовыми к ревью.",
+		}),
+		prMerged: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_merged_total",
+			Help:      "Количество PR, переведённых в MERGED.",
+		}),
+		reassignments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_reassignments_total",
+			Help:      "Количество замен ревьюверов, включая замены при деактивации.",
+		}),
+		noCandidate: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_no_candidate_total",
+			Help:      "Количество замен, не выполненных из-за отсутствия кандидатов (NO_CANDIDATE).",
+		}),
+		prUnderstaffed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_understaffed_total",
+			Help:      "Количество PR, получивших при создании или markReady меньше ревьюверов, чем требует команда.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.queryDuration,
+		m.prCreated, m.prReady, m.prMerged, m.reassignments, m.noCandidate, m.prUnderstaffed,
+	)
+	return m
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware считает запросы и их длительность. Предназначен для StdHTTPServerOptions.Middlewares:
+// там маршрут уже сопоставлен, и в метку route попадает шаблон из спецификации, а не сырой путь.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		// Шаблон ServeMux имеет вид "GET /team/get"
+		route := r.Pattern
+		if _, path, ok := strings.Cut(route, " "); ok {
+			route = path
+		}
+		m.httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(sw.status)).Inc()
+		m.httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusWriter запоминает код ответа
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// RegisterDBStats добавляет метрики пула соединений; stats вызывается при каждом сборе метрик
+func (m *Metrics) RegisterDBStats(stats func() sql.DBStats) {
+	gauge := func(name, help string, value func(s sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "db_pool",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return value(stats()) })
+	}
+	counter := func(name, help string, value func(s sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db_pool",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return value(stats()) })
+	}
+
+	m.registry.MustRegister(
+		gauge("max_open_connections", "Ограничение на число открытых соединений (0 — без ограничения).",
+			func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }),
+		gauge("open_connections", "Открытые соединения.",
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		gauge("in_use_connections", "Соединения, занятые запросами.",
+			func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		gauge("idle_connections", "Простаивающие соединения.",
+			func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		counter("wait_count_total", "Сколько раз запрос ждал свободного соединения.",
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		counter("wait_duration_seconds_total", "Суммарное время ожидания свободного соединения.",
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+		counter("max_idle_closed_total", "Соединения, закрытые из-за ограничения на простаивающие.",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }),
+		counter("max_lifetime_closed_total", "Соединения, закрытые по истечении времени жизни.",
+			func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }),
+	)
+}
+
+// PullRequestCreated реализует service.Recorder
+func (m *Metrics) PullRequestCreated(assigned, required int) {
+	m.prCreated.Inc()
+	if assigned < required {
+		m.prUnderstaffed.Inc()
+	}
+}
+
+// PullRequestReady реализует service.Recorder
+func (m *Metrics) PullRequestReady(assigned, required int) {
+	m.prReady.Inc()
+	if assigned < required {
+		m.prUnderstaffed.Inc()
+	}
+}
+
+// PullRequestMerged реализует service.Recorder
+func (m *Metrics) PullRequestMerged() {
+	m.prMerged.Inc()
+}
+
+// ReviewerReassigned реализует service.Recorder
+func (m *Metrics) ReviewerReassigned() {
+	m.reassignments.Inc()
+}
+
+// NoCandidate реализует service.Recorder
+func (m *Metrics) NoCandidate() {
+	m.noCandidate.Inc()
+}
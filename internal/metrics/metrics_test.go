@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pr-reviewer/internal/api"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddlewareLabelsRoutePattern(t *testing.T) {
+	m := New()
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /team/get", m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/team/get?team_name=backend", nil))
+
+	if got := testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/team/get", "404")); got != 1 {
+		t.Fatalf("Ожидался 1 запрос с меткой маршрута, получено %v", got)
+	}
+}
+
+func TestMiddlewareCountsParamErrors(t *testing.T) {
+	m := New()
+
+	svc := service.NewService(db.NewMemoryStorage(), service.Options{})
+	handler := api.HandlerWithOptions(api.NewServer(svc, api.Options{}), api.StdHTTPServerOptions{
+		Middlewares:      []api.MiddlewareFunc{m.Middleware},
+		ErrorHandlerFunc: api.ParamErrorHandler(m.Middleware),
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/getReview?user_id=u1&limit=abc", nil))
+
+	if got := testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/users/getReview", "400")); got != 1 {
+		t.Fatalf("Ожидался 1 запрос с ошибкой параметров, получено %v", got)
+	}
+}
+
+func TestRecorderCountsUnderstaffed(t *testing.T) {
+	m := New()
+
+	m.PullRequestCreated(2, 2)
+	m.PullRequestCreated(1, 3)
+	m.PullRequestCreated(0, 0)
+	m.PullRequestReady(1, 2)
+	m.NoCandidate()
+
+	if got := testutil.ToFloat64(m.prCreated); got != 3 {
+		t.Fatalf("Ожидалось 3 созданных PR, получено %v", got)
+	}
+	if got := testutil.ToFloat64(m.prReady); got != 1 {
+		t.Fatalf("Ожидался 1 черновик, помеченный готовым, получено %v", got)
+	}
+	if got := testutil.ToFloat64(m.prUnderstaffed); got != 2 {
+		t.Fatalf("Ожидалось 2 PR с нехваткой ревьюверов, получено %v", got)
+	}
+	if got := testutil.ToFloat64(m.noCandidate); got != 1 {
+		t.Fatalf("Ожидался 1 исход NO_CANDIDATE, получено %v", got)
+	}
+}
+
+func TestInstrumentRepositoryObservesTxOperations(t *testing.T) {
+	ctx := context.Background()
+	m := New()
+	repo := m.InstrumentRepository(db.NewMemoryStorage())
+
+	err := repo.InTx(ctx, func(tx db.Repository) error {
+		return tx.SaveTeam(ctx, &models.Team{TeamName: "backend"})
+	})
+	if err != nil {
+		t.Fatalf("Ошибка сохранения команды: %v", err)
+	}
+	if _, err := repo.GetTeam(ctx, "backend"); err != nil {
+		t.Fatalf("Ошибка получения команды: %v", err)
+	}
+
+	if got := testutil.CollectAndCount(m.queryDuration); got != 2 {
+		t.Fatalf("Ожидались гистограммы для 2 операций, получено %d", got)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `pr_reviewer_db_query_duration_seconds_count{operation="SaveTeam"} 1`) {
+		t.Fatalf("Метрика операции внутри транзакции не найдена:\n%s", rec.Body.String())
+	}
+}
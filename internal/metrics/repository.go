@@ -0,0 +1,213 @@
+package metrics
+
+import (
+	"context"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ db.Repository    = (*repository)(nil)
+	_ service.Recorder = (*Metrics)(nil)
+)
+
+// repository измеряет длительность каждой операции вложенного хранилища
+type repository struct {
+	next          db.Repository
+	queryDuration *prometheus.HistogramVec
+}
+
+// InstrumentRepository оборачивает хранилище, добавляя метрику db_query_duration_seconds.
+// Операции внутри InTx измеряются по отдельности.
+func (m *Metrics) InstrumentRepository(next db.Repository) db.Repository {
+	return &repository{next: next, queryDuration: m.queryDuration}
+}
+
+func (r *repository) observe(operation string, start time.Time) {
+	r.queryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func (r *repository) InTx(ctx context.Context, fn func(tx db.Repository) error) error {
+	return r.next.InTx(ctx, func(tx db.Repository) error {
+		return fn(&repository{next: tx, queryDuration: r.queryDuration})
+	})
+}
+
+func (r *repository) TeamExists(ctx context.Context, name string) (bool, error) {
+	defer r.observe("TeamExists", time.Now())
+	return r.next.TeamExists(ctx, name)
+}
+
+func (r *repository) SaveTeam(ctx context.Context, team *models.Team) error {
+	defer r.observe("SaveTeam", time.Now())
+	return r.next.SaveTeam(ctx, team)
+}
+
+func (r *repository) GetTeam(ctx context.Context, name string) (*models.Team, error) {
+	defer r.observe("GetTeam", time.Now())
+	return r.next.GetTeam(ctx, name)
+}
+
+func (r *repository) SetTeamReviewersRequired(ctx context.Context, name string, count int) error {
+	defer r.observe("SetTeamReviewersRequired", time.Now())
+	return r.next.SetTeamReviewersRequired(ctx, name, count)
+}
+
+func (r *repository) GetTeamSettings(ctx context.Context, name string) (*models.TeamSettings, error) {
+	defer r.observe("GetTeamSettings", time.Now())
+	return r.next.GetTeamSettings(ctx, name)
+}
+
+func (r *repository) SaveTeamSettings(ctx context.Context, settings *models.TeamSettings) error {
+	defer r.observe("SaveTeamSettings", time.Now())
+	return r.next.SaveTeamSettings(ctx, settings)
+}
+
+func (r *repository) AdvanceRoundRobinCursor(ctx context.Context, name string, step int) (int64, error) {
+	defer r.observe("AdvanceRoundRobinCursor", time.Now())
+	return r.next.AdvanceRoundRobinCursor(ctx, name, step)
+}
+
+func (r *repository) SaveUser(ctx context.Context, user *models.User) error {
+	defer r.observe("SaveUser", time.Now())
+	return r.next.SaveUser(ctx, user)
+}
+
+func (r *repository) GetUser(ctx context.Context, id string) (*models.User, error) {
+	defer r.observe("GetUser", time.Now())
+	return r.next.GetUser(ctx, id)
+}
+
+func (r *repository) GetUsersByUsername(ctx context.Context, username string) ([]models.User, error) {
+	defer r.observe("GetUsersByUsername", time.Now())
+	return r.next.GetUsersByUsername(ctx, username)
+}
+
+func (r *repository) SaveGitHubUser(ctx context.Context, login, userId string) error {
+	defer r.observe("SaveGitHubUser", time.Now())
+	return r.next.SaveGitHubUser(ctx, login, userId)
+}
+
+func (r *repository) GetGitHubUser(ctx context.Context, login string) (string, error) {
+	defer r.observe("GetGitHubUser", time.Now())
+	return r.next.GetGitHubUser(ctx, login)
+}
+
+func (r *repository) PullRequestExists(ctx context.Context, id string) (bool, error) {
+	defer r.observe("PullRequestExists", time.Now())
+	return r.next.PullRequestExists(ctx, id)
+}
+
+func (r *repository) CreatePullRequest(ctx context.Context, pr *models.PullRequest) (bool, error) {
+	defer r.observe("CreatePullRequest", time.Now())
+	return r.next.CreatePullRequest(ctx, pr)
+}
+
+func (r *repository) SavePullRequest(ctx context.Context, pr *models.PullRequest) error {
+	defer r.observe("SavePullRequest", time.Now())
+	return r.next.SavePullRequest(ctx, pr)
+}
+
+func (r *repository) GetPullRequest(ctx context.Context, id string) (*models.PullRequest, error) {
+	defer r.observe("GetPullRequest", time.Now())
+	return r.next.GetPullRequest(ctx, id)
+}
+
+func (r *repository) GetPullRequestForUpdate(ctx context.Context, id string) (*models.PullRequest, error) {
+	defer r.observe("GetPullRequestForUpdate", time.Now())
+	return r.next.GetPullRequestForUpdate(ctx, id)
+}
+
+func (r *repository) SaveReview(ctx context.Context, prId string, review models.Review) error {
+	defer r.observe("SaveReview", time.Now())
+	return r.next.SaveReview(ctx, prId, review)
+}
+
+func (r *repository) GetPullRequestsByReviewer(ctx context.Context, userId string) ([]models.PullRequest, error) {
+	defer r.observe("GetPullRequestsByReviewer", time.Now())
+	return r.next.GetPullRequestsByReviewer(ctx, userId)
+}
+
+func (r *repository) ListPullRequests(ctx context.Context, filter db.PullRequestFilter) ([]models.PullRequest, error) {
+	defer r.observe("ListPullRequests", time.Now())
+	return r.next.ListPullRequests(ctx, filter)
+}
+
+func (r *repository) GetOpenReviewCounts(ctx context.Context, userIds []string) (map[string]int, error) {
+	defer r.observe("GetOpenReviewCounts", time.Now())
+	return r.next.GetOpenReviewCounts(ctx, userIds)
+}
+
+func (r *repository) SaveReassignment(ctx context.Context, prId, oldUserId, newUserId string, at time.Time) error {
+	defer r.observe("SaveReassignment", time.Now())
+	return r.next.SaveReassignment(ctx, prId, oldUserId, newUserId, at)
+}
+
+func (r *repository) AppendPullRequestEvents(ctx context.Context, events []models.PullRequestEvent) error {
+	defer r.observe("AppendPullRequestEvents", time.Now())
+	return r.next.AppendPullRequestEvents(ctx, events)
+}
+
+func (r *repository) GetPullRequestEvents(ctx context.Context, prId string) ([]models.PullRequestEvent, error) {
+	defer r.observe("GetPullRequestEvents", time.Now())
+	return r.next.GetPullRequestEvents(ctx, prId)
+}
+
+func (r *repository) GetReviewerStats(ctx context.Context, from, to *time.Time, teamName *string) ([]models.ReviewerStats, error) {
+	defer r.observe("GetReviewerStats", time.Now())
+	return r.next.GetReviewerStats(ctx, from, to, teamName)
+}
+
+func (r *repository) CreateWebhookSubscription(ctx context.Context, sub *models.WebhookSubscription, secret string) error {
+	defer r.observe("CreateWebhookSubscription", time.Now())
+	return r.next.CreateWebhookSubscription(ctx, sub, secret)
+}
+
+func (r *repository) ListWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	defer r.observe("ListWebhookSubscriptions", time.Now())
+	return r.next.ListWebhookSubscriptions(ctx)
+}
+
+func (r *repository) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	defer r.observe("DeleteWebhookSubscription", time.Now())
+	return r.next.DeleteWebhookSubscription(ctx, id)
+}
+
+func (r *repository) EnqueueWebhook(ctx context.Context, event models.WebhookEventType, payload []byte, at time.Time) error {
+	defer r.observe("EnqueueWebhook", time.Now())
+	return r.next.EnqueueWebhook(ctx, event, payload, at)
+}
+
+func (r *repository) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]db.WebhookDelivery, error) {
+	defer r.observe("ClaimWebhookDeliveries", time.Now())
+	return r.next.ClaimWebhookDeliveries(ctx, now, leaseUntil, limit)
+}
+
+func (r *repository) CompleteWebhookDelivery(ctx context.Context, id int64, at time.Time) error {
+	defer r.observe("CompleteWebhookDelivery", time.Now())
+	return r.next.CompleteWebhookDelivery(ctx, id, at)
+}
+
+func (r *repository) RetryWebhookDelivery(ctx context.Context, id int64, lastErr string, next time.Time) error {
+	defer r.observe("RetryWebhookDelivery", time.Now())
+	return r.next.RetryWebhookDelivery(ctx, id, lastErr, next)
+}
+
+func (r *repository) FailWebhookDelivery(ctx context.Context, id int64, lastErr string, at time.Time) error {
+	defer r.observe("FailWebhookDelivery", time.Now())
+	return r.next.FailWebhookDelivery(ctx, id, lastErr, at)
+}
+
+func (r *repository) Ping(ctx context.Context) error {
+	defer r.observe("Ping", time.Now())
+	return r.next.Ping(ctx)
+}
+
+func (r *repository) MigrationVersion(ctx context.Context) (int64, error) {
+	defer r.observe("MigrationVersion", time.Now())
+	return r.next.MigrationVersion(ctx)
+}
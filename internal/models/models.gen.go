@@ -9,26 +9,110 @@ import (
 
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	CONFLICT           ErrorResponseErrorCode = "CONFLICT"
+	INTERNAL           ErrorResponseErrorCode = "INTERNAL"
+	INVALIDREQUEST     ErrorResponseErrorCode = "INVALID_REQUEST"
+	INVALIDSETTINGS    ErrorResponseErrorCode = "INVALID_SETTINGS"
+	INVALIDSIGNATURE   ErrorResponseErrorCode = "INVALID_SIGNATURE"
+	NOCANDIDATE        ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED        ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTENOUGHAPPROVALS ErrorResponseErrorCode = "NOT_ENOUGH_APPROVALS"
+	NOTFOUND           ErrorResponseErrorCode = "NOT_FOUND"
+	PRCLOSED           ErrorResponseErrorCode = "PR_CLOSED"
+	PRDRAFT            ErrorResponseErrorCode = "PR_DRAFT"
+	PREXISTS           ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED           ErrorResponseErrorCode = "PR_MERGED"
+	TEAMEXISTS         ErrorResponseErrorCode = "TEAM_EXISTS"
+	UNAVAILABLE        ErrorResponseErrorCode = "UNAVAILABLE"
+)
+
+// Defines values for HealthStatusStatus.
+const (
+	Ok          HealthStatusStatus = "ok"
+	Unavailable HealthStatusStatus = "unavailable"
 )
 
 // Defines values for PullRequestStatus.
 const (
+	PullRequestStatusCLOSED PullRequestStatus = "CLOSED"
 	PullRequestStatusMERGED PullRequestStatus = "MERGED"
 	PullRequestStatusOPEN   PullRequestStatus = "OPEN"
 )
 
+// Defines values for PullRequestEventType.
+const (
+	Closed           PullRequestEventType = "closed"
+	Created          PullRequestEventType = "created"
+	Merged           PullRequestEventType = "merged"
+	ReadyForReview   PullRequestEventType = "ready_for_review"
+	Reopened         PullRequestEventType = "reopened"
+	Reviewed         PullRequestEventType = "reviewed"
+	ReviewerAssigned PullRequestEventType = "reviewer_assigned"
+	ReviewerReplaced PullRequestEventType = "reviewer_replaced"
+)
+
 // Defines values for PullRequestShortStatus.
 const (
+	PullRequestShortStatusCLOSED PullRequestShortStatus = "CLOSED"
 	PullRequestShortStatusMERGED PullRequestShortStatus = "MERGED"
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
+// Defines values for ReviewVerdict.
+const (
+	APPROVED         ReviewVerdict = "APPROVED"
+	CHANGESREQUESTED ReviewVerdict = "CHANGES_REQUESTED"
+	COMMENTED        ReviewVerdict = "COMMENTED"
+)
+
+// Defines values for ReviewerStrategy.
+const (
+	FirstN      ReviewerStrategy = "first_n"
+	LeastLoaded ReviewerStrategy = "least_loaded"
+	Random      ReviewerStrategy = "random"
+	RoundRobin  ReviewerStrategy = "round_robin"
+)
+
+// Defines values for WebhookEventType.
+const (
+	PullRequestClosed         WebhookEventType = "pull_request.closed"
+	PullRequestCreated        WebhookEventType = "pull_request.created"
+	PullRequestMerged         WebhookEventType = "pull_request.merged"
+	PullRequestReadyForReview WebhookEventType = "pull_request.ready_for_review"
+	PullRequestReassigned     WebhookEventType = "pull_request.reassigned"
+	PullRequestReopened       WebhookEventType = "pull_request.reopened"
+	PullRequestReviewed       WebhookEventType = "pull_request.reviewed"
+	UserDeactivated           WebhookEventType = "user.deactivated"
+)
+
+// Defines values for SortQuery.
+const (
+	CreatedAsc  SortQuery = "created_asc"
+	CreatedDesc SortQuery = "created_desc"
+)
+
+// Defines values for GetPullRequestListParamsStatus.
+const (
+	GetPullRequestListParamsStatusCLOSED GetPullRequestListParamsStatus = "CLOSED"
+	GetPullRequestListParamsStatusMERGED GetPullRequestListParamsStatus = "MERGED"
+	GetPullRequestListParamsStatusOPEN   GetPullRequestListParamsStatus = "OPEN"
+)
+
+// Defines values for GetUsersGetReviewParamsStatus.
+const (
+	GetUsersGetReviewParamsStatusCLOSED GetUsersGetReviewParamsStatus = "CLOSED"
+	GetUsersGetReviewParamsStatusMERGED GetUsersGetReviewParamsStatus = "MERGED"
+	GetUsersGetReviewParamsStatusOPEN   GetUsersGetReviewParamsStatus = "OPEN"
+)
+
+// DeactivationReport defines model for DeactivationReport.
+type DeactivationReport struct {
+	DeactivatedUsers []string             `json:"deactivated_users"`
+	NotReassigned    []FailedReassignment `json:"not_reassigned"`
+	Reassigned       []Reassignment       `json:"reassigned"`
+	TeamName         string               `json:"team_name"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
@@ -40,24 +124,108 @@ type ErrorResponse struct {
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// FailedReassignment defines model for FailedReassignment.
+type FailedReassignment struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// Reason Код ошибки, из-за которой замена не выполнена (например, NO_CANDIDATE)
+	Reason string `json:"reason"`
+
+	// UserId user_id ревьювера, оставшегося назначенным
+	UserId string `json:"user_id"`
+}
+
+// HealthStatus defines model for HealthStatus.
+type HealthStatus struct {
+	// MigrationVersion Версия последней применённой миграции БД (только для /health/ready)
+	MigrationVersion *int64             `json:"migration_version,omitempty"`
+	Status           HealthStatusStatus `json:"status"`
+}
+
+// HealthStatusStatus defines model for HealthStatus.Status.
+type HealthStatusStatus string
+
+// IntegrationResult defines model for IntegrationResult.
+type IntegrationResult struct {
+	// Action Действие из входящего события
+	Action      *string      `json:"action,omitempty"`
+	PullRequest *PullRequest `json:"pull_request,omitempty"`
+
+	// Result created — PR создан, merged — PR объединён, closed — PR закрыт, reopened — PR открыт заново,
+	// unchanged — событие уже было обработано, ignored — событие не требует действий
+	Result string `json:"result"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
-	// AssignedReviewers user_id назначенных ревьюверов (0..2)
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	AuthorId          string            `json:"author_id"`
-	CreatedAt         *time.Time        `json:"createdAt"`
-	MergedAt          *time.Time        `json:"mergedAt"`
-	PullRequestId     string            `json:"pull_request_id"`
-	PullRequestName   string            `json:"pull_request_name"`
-	Status            PullRequestStatus `json:"status"`
+	// AssignedReviewers user_id назначенных ревьюверов (0..reviewers_required команды)
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	AuthorId          string   `json:"author_id"`
+
+	// ClosedAt Время закрытия без merge; сбрасывается при повторном открытии
+	ClosedAt  *time.Time `json:"closedAt"`
+	CreatedAt *time.Time `json:"createdAt"`
+
+	// Draft Черновик; ревьюверы назначаются только после /pullRequest/markReady
+	Draft           bool       `json:"draft"`
+	MergedAt        *time.Time `json:"mergedAt"`
+	PullRequestId   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+
+	// Reviews Последний вердикт каждого назначенного ревьювера, который его оставил; снятый ревьювер теряет вердикт
+	Reviews []Review          `json:"reviews"`
+	Status  PullRequestStatus `json:"status"`
 }
 
 // PullRequestStatus defines model for PullRequest.Status.
 type PullRequestStatus string
 
+// PullRequestEvent defines model for PullRequestEvent.
+type PullRequestEvent struct {
+	// Actor Значение заголовка X-Actor запроса, вызвавшего изменение, или api, если заголовок не передан
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"createdAt"`
+	EventId   int64     `json:"event_id"`
+
+	// EventType created — PR создан,
+	// reviewer_assigned — ревьювер назначен (reviewer_id),
+	// reviewer_replaced — ревьювер заменён (old_reviewer_id → new_reviewer_id),
+	// merged — PR объединён,
+	// closed — PR закрыт без merge,
+	// reopened — закрытый PR открыт заново,
+	// ready_for_review — черновик помечен готовым к ревью,
+	// reviewed — ревьювер оставил вердикт (reviewer_id, verdict)
+	EventType     PullRequestEventType `json:"event_type"`
+	NewReviewerId *string              `json:"new_reviewer_id,omitempty"`
+	OldReviewerId *string              `json:"old_reviewer_id,omitempty"`
+	PullRequestId string               `json:"pull_request_id"`
+
+	// Reason Причина назначения или замены: auto — при создании PR или /pullRequest/markReady, manual — /pullRequest/reassign,
+	// user_deactivated — /team/deactivateUsers, reviewers_required — добор до reviewers_required команды,
+	// reopened — замена неактивного ревьювера при /pullRequest/reopen
+	Reason     *string `json:"reason,omitempty"`
+	ReviewerId *string `json:"reviewer_id,omitempty"`
+
+	// Verdict APPROVED — PR одобрен,
+	// CHANGES_REQUESTED — нужны изменения,
+	// COMMENTED — комментарий без решения
+	Verdict *ReviewVerdict `json:"verdict,omitempty"`
+}
+
+// PullRequestEventType created — PR создан,
+// reviewer_assigned — ревьювер назначен (reviewer_id),
+// reviewer_replaced — ревьювер заменён (old_reviewer_id → new_reviewer_id),
+// merged — PR объединён,
+// closed — PR закрыт без merge,
+// reopened — закрытый PR открыт заново,
+// ready_for_review — черновик помечен готовым к ревью,
+// reviewed — ревьювер оставил вердикт (reviewer_id, verdict)
+type PullRequestEventType string
+
 // PullRequestShort defines model for PullRequestShort.
 type PullRequestShort struct {
 	AuthorId        string                 `json:"author_id"`
+	CreatedAt       *time.Time             `json:"createdAt"`
 	PullRequestId   string                 `json:"pull_request_id"`
 	PullRequestName string                 `json:"pull_request_name"`
 	Status          PullRequestShortStatus `json:"status"`
@@ -66,10 +234,61 @@ type PullRequestShort struct {
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// Reassignment defines model for Reassignment.
+type Reassignment struct {
+	NewUserId     string `json:"new_user_id"`
+	OldUserId     string `json:"old_user_id"`
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// Review defines model for Review.
+type Review struct {
+	ReviewerId  string    `json:"reviewer_id"`
+	SubmittedAt time.Time `json:"submittedAt"`
+
+	// Verdict APPROVED — PR одобрен,
+	// CHANGES_REQUESTED — нужны изменения,
+	// COMMENTED — комментарий без решения
+	Verdict ReviewVerdict `json:"verdict"`
+}
+
+// ReviewVerdict APPROVED — PR одобрен,
+// CHANGES_REQUESTED — нужны изменения,
+// COMMENTED — комментарий без решения
+type ReviewVerdict string
+
+// ReviewerStats defines model for ReviewerStats.
+type ReviewerStats struct {
+	// Assignments Сколько раз пользователь назначался ревьювером, включая снятые назначения
+	Assignments int `json:"assignments"`
+
+	// MergedReviewed Объединённые PR, где пользователь ревьювер
+	MergedReviewed int `json:"merged_reviewed"`
+
+	// OpenAssignments Текущие назначения на открытые PR
+	OpenAssignments int `json:"open_assignments"`
+
+	// ReassignedAway Сколько раз пользователя заменили на другого ревьювера
+	ReassignedAway int    `json:"reassigned_away"`
+	TeamName       string `json:"team_name"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+}
+
+// ReviewerStrategy Стратегия выбора ревьюверов:
+// first_n — первые N активных участников,
+// random — случайные участники,
+// round_robin — по кругу с сохраняемым курсором,
+// least_loaded — наименее загруженные открытыми ревью
+type ReviewerStrategy string
+
 // Team defines model for Team.
 type Team struct {
-	Members  []TeamMember `json:"members"`
-	TeamName string       `json:"team_name"`
+	Members []TeamMember `json:"members"`
+
+	// ReviewersRequired Сколько ревьюверов назначается на PR команды; если не задано, используется значение из конфигурации сервиса
+	ReviewersRequired *int   `json:"reviewers_required,omitempty"`
+	TeamName          string `json:"team_name"`
 }
 
 // TeamMember defines model for TeamMember.
@@ -79,6 +298,33 @@ type TeamMember struct {
 	Username string `json:"username"`
 }
 
+// TeamSettings defines model for TeamSettings.
+type TeamSettings struct {
+	// ApprovalsRequired Сколько ревьюверов PR должны одобрить его (APPROVED) перед merge; 0 отключает проверку.
+	// Не больше reviewers_required команды. Если PR назначено меньше ревьюверов,
+	// достаточно одобрения каждого из них. В ответах заполнено всегда;
+	// если не передано в /team/setSettings, текущее значение не меняется.
+	ApprovalsRequired *int `json:"approvals_required,omitempty"`
+
+	// ReviewerStrategy Стратегия выбора ревьюверов:
+	// first_n — первые N активных участников,
+	// random — случайные участники,
+	// round_robin — по кругу с сохраняемым курсором,
+	// least_loaded — наименее загруженные открытыми ревью
+	ReviewerStrategy ReviewerStrategy `json:"reviewer_strategy"`
+	TeamName         string           `json:"team_name"`
+}
+
+// TeamStats Суммы ReviewerStats по участникам команды
+type TeamStats struct {
+	Assignments     int    `json:"assignments"`
+	Members         int    `json:"members"`
+	MergedReviewed  int    `json:"merged_reviewed"`
+	OpenAssignments int    `json:"open_assignments"`
+	ReassignedAway  int    `json:"reassigned_away"`
+	TeamName        string `json:"team_name"`
+}
+
 // User defines model for User.
 type User struct {
 	IsActive bool   `json:"is_active"`
@@ -87,19 +333,167 @@ type User struct {
 	Username string `json:"username"`
 }
 
+// WebhookEventType pull_request.created — PR создан и получил ревьюверов (черновик — без ревьюверов),
+// pull_request.ready_for_review — черновик помечен готовым и получил ревьюверов,
+// pull_request.reassigned — ревьювер заменён (вручную, при деактивации или повторном открытии),
+// pull_request.reviewed — ревьювер оставил вердикт,
+// pull_request.merged — PR объединён,
+// pull_request.closed — PR закрыт без merge,
+// pull_request.reopened — закрытый PR открыт заново,
+// user.deactivated — пользователь деактивирован
+type WebhookEventType string
+
+// WebhookPayload Тело запроса к получателю вебхука. Подпись HMAC-SHA256 тела с секретом подписки
+// передаётся в заголовке X-Webhook-Signature-256 в виде sha256=<hex>.
+type WebhookPayload struct {
+	Actor string `json:"actor"`
+
+	// Event pull_request.created — PR создан и получил ревьюверов (черновик — без ревьюверов),
+	// pull_request.ready_for_review — черновик помечен готовым и получил ревьюверов,
+	// pull_request.reassigned — ревьювер заменён (вручную, при деактивации или повторном открытии),
+	// pull_request.reviewed — ревьювер оставил вердикт,
+	// pull_request.merged — PR объединён,
+	// pull_request.closed — PR закрыт без merge,
+	// pull_request.reopened — закрытый PR открыт заново,
+	// user.deactivated — пользователь деактивирован
+	Event         WebhookEventType `json:"event"`
+	NewReviewerId *string          `json:"new_reviewer_id,omitempty"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+	OldReviewerId *string          `json:"old_reviewer_id,omitempty"`
+	PullRequest   *PullRequest     `json:"pull_request,omitempty"`
+	Reason        *string          `json:"reason,omitempty"`
+	User          *User            `json:"user,omitempty"`
+}
+
+// WebhookSubscription defines model for WebhookSubscription.
+type WebhookSubscription struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Events События, на которые подписан получатель; пустой список — все события
+	Events         []WebhookEventType `json:"events"`
+	SubscriptionId string             `json:"subscription_id"`
+	Url            string             `json:"url"`
+}
+
+// CursorQuery defines model for CursorQuery.
+type CursorQuery = string
+
+// FromQuery defines model for FromQuery.
+type FromQuery = time.Time
+
+// LimitQuery defines model for LimitQuery.
+type LimitQuery = int
+
+// SortQuery defines model for SortQuery.
+type SortQuery string
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
+// ToQuery defines model for ToQuery.
+type ToQuery = time.Time
+
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
+// PostIntegrationsGithubSetUserJSONBody defines parameters for PostIntegrationsGithubSetUser.
+type PostIntegrationsGithubSetUserJSONBody struct {
+	GithubLogin string `json:"github_login"`
+	UserId      string `json:"user_id"`
+}
+
+// PostIntegrationsGithubWebhookJSONBody defines parameters for PostIntegrationsGithubWebhook.
+type PostIntegrationsGithubWebhookJSONBody map[string]interface{}
+
+// PostIntegrationsGithubWebhookParams defines parameters for PostIntegrationsGithubWebhook.
+type PostIntegrationsGithubWebhookParams struct {
+	// XGitHubEvent Тип события GitHub
+	XGitHubEvent *string `json:"X-GitHub-Event,omitempty"`
+
+	// XHubSignature256 HMAC-SHA256 тела с секретом вебхука в виде sha256=<hex>
+	XHubSignature256 *string `json:"X-Hub-Signature-256,omitempty"`
+}
+
+// PostIntegrationsGitlabWebhookJSONBody defines parameters for PostIntegrationsGitlabWebhook.
+type PostIntegrationsGitlabWebhookJSONBody map[string]interface{}
+
+// PostIntegrationsGitlabWebhookParams defines parameters for PostIntegrationsGitlabWebhook.
+type PostIntegrationsGitlabWebhookParams struct {
+	// XGitlabEvent Тип события GitLab
+	XGitlabEvent *string `json:"X-Gitlab-Event,omitempty"`
+
+	// XGitlabToken Секретный токен, заданный в настройках вебхука
+	XGitlabToken *string `json:"X-Gitlab-Token,omitempty"`
+}
+
+// PostPullRequestCloseJSONBody defines parameters for PostPullRequestClose.
+type PostPullRequestCloseJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
 // PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
 type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
+	AuthorId string `json:"author_id"`
+
+	// Draft Создать черновик без ревьюверов
+	Draft           *bool  `json:"draft,omitempty"`
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 }
 
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestHistoryParams defines parameters for GetPullRequestHistory.
+type GetPullRequestHistoryParams struct {
+	// PullRequestId Идентификатор PR
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestListParams defines parameters for GetPullRequestList.
+type GetPullRequestListParams struct {
+	// AuthorId Только PR этого автора
+	AuthorId *string `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// TeamName Только PR, автор которых состоит в этой команде
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// Status Только PR в этом статусе
+	Status *GetPullRequestListParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// ReviewerId Только PR, на которые назначен этот ревьювер
+	ReviewerId *string `form:"reviewer_id,omitempty" json:"reviewer_id,omitempty"`
+
+	// Name Подстрока названия PR без учёта регистра
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// CreatedAfter Только PR, созданные не раньше этого момента
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Только PR, созданные раньше этого момента
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// Sort Порядок по createdAt, затем по pull_request_id
+	Sort *SortQuery `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor Значение next_cursor из предыдущего ответа; фильтры и sort должны совпадать
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Размер страницы
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetPullRequestListParamsStatus defines parameters for GetPullRequestList.
+type GetPullRequestListParamsStatus string
+
+// PostPullRequestMarkReadyJSONBody defines parameters for PostPullRequestMarkReady.
+type PostPullRequestMarkReadyJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
 type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
@@ -111,35 +505,164 @@ type PostPullRequestReassignJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
 }
 
+// PostPullRequestReopenJSONBody defines parameters for PostPullRequestReopen.
+type PostPullRequestReopenJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+}
+
+// PostPullRequestReviewJSONBody defines parameters for PostPullRequestReview.
+type PostPullRequestReviewJSONBody struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
+
+	// Verdict APPROVED — PR одобрен,
+	// CHANGES_REQUESTED — нужны изменения,
+	// COMMENTED — комментарий без решения
+	Verdict ReviewVerdict `json:"verdict"`
+}
+
+// GetStatsReviewersParams defines parameters for GetStatsReviewers.
+type GetStatsReviewersParams struct {
+	// From Начало окна (включительно)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец окна (не включительно)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+
+	// TeamName Ограничить статистику одной командой
+	TeamName *string `form:"team_name,omitempty" json:"team_name,omitempty"`
+}
+
+// GetStatsTeamsParams defines parameters for GetStatsTeams.
+type GetStatsTeamsParams struct {
+	// From Начало окна (включительно)
+	From *FromQuery `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Конец окна (не включительно)
+	To *ToQuery `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// PostTeamDeactivateUsersJSONBody defines parameters for PostTeamDeactivateUsers.
+type PostTeamDeactivateUsersJSONBody struct {
+	TeamName string   `json:"team_name"`
+	UserIds  []string `json:"user_ids"`
+}
+
 // GetTeamGetParams defines parameters for GetTeamGet.
 type GetTeamGetParams struct {
 	// TeamName Уникальное имя команды
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
+// GetTeamGetSettingsParams defines parameters for GetTeamGetSettings.
+type GetTeamGetSettingsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamSetReviewersRequiredJSONBody defines parameters for PostTeamSetReviewersRequired.
+type PostTeamSetReviewersRequiredJSONBody struct {
+	ReviewersRequired int    `json:"reviewers_required"`
+	TeamName          string `json:"team_name"`
+}
+
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
 type GetUsersGetReviewParams struct {
 	// UserId Идентификатор пользователя
 	UserId UserIdQuery `form:"user_id" json:"user_id"`
+
+	// Status Только PR в этом статусе
+	Status *GetUsersGetReviewParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// CreatedAfter Только PR, созданные не раньше этого момента
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+
+	// CreatedBefore Только PR, созданные раньше этого момента
+	CreatedBefore *time.Time `form:"created_before,omitempty" json:"created_before,omitempty"`
+
+	// Sort Порядок по createdAt, затем по pull_request_id
+	Sort *SortQuery `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor Значение next_cursor из предыдущего ответа; фильтры и sort должны совпадать
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Размер страницы
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// GetUsersGetReviewParamsStatus defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsStatus string
+
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
 type PostUsersSetIsActiveJSONBody struct {
 	IsActive bool   `json:"is_active"`
 	UserId   string `json:"user_id"`
 }
 
+// PostWebhooksAddJSONBody defines parameters for PostWebhooksAdd.
+type PostWebhooksAddJSONBody struct {
+	// Events Фильтр событий; если не задан, доставляются все
+	Events *[]WebhookEventType `json:"events,omitempty"`
+
+	// Secret Общий секрет для подписи тела запроса
+	Secret string `json:"secret"`
+
+	// Url Абсолютный http(s) URL получателя
+	Url string `json:"url"`
+}
+
+// PostWebhooksDeleteJSONBody defines parameters for PostWebhooksDelete.
+type PostWebhooksDeleteJSONBody struct {
+	SubscriptionId string `json:"subscription_id"`
+}
+
+// PostIntegrationsGithubSetUserJSONRequestBody defines body for PostIntegrationsGithubSetUser for application/json ContentType.
+type PostIntegrationsGithubSetUserJSONRequestBody PostIntegrationsGithubSetUserJSONBody
+
+// PostIntegrationsGithubWebhookJSONRequestBody defines body for PostIntegrationsGithubWebhook for application/json ContentType.
+type PostIntegrationsGithubWebhookJSONRequestBody PostIntegrationsGithubWebhookJSONBody
+
+// PostIntegrationsGitlabWebhookJSONRequestBody defines body for PostIntegrationsGitlabWebhook for application/json ContentType.
+type PostIntegrationsGitlabWebhookJSONRequestBody PostIntegrationsGitlabWebhookJSONBody
+
+// PostPullRequestCloseJSONRequestBody defines body for PostPullRequestClose for application/json ContentType.
+type PostPullRequestCloseJSONRequestBody PostPullRequestCloseJSONBody
+
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
 type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
 
+// PostPullRequestMarkReadyJSONRequestBody defines body for PostPullRequestMarkReady for application/json ContentType.
+type PostPullRequestMarkReadyJSONRequestBody PostPullRequestMarkReadyJSONBody
+
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
 // PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
 type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 
+// PostPullRequestReopenJSONRequestBody defines body for PostPullRequestReopen for application/json ContentType.
+type PostPullRequestReopenJSONRequestBody PostPullRequestReopenJSONBody
+
+// PostPullRequestReviewJSONRequestBody defines body for PostPullRequestReview for application/json ContentType.
+type PostPullRequestReviewJSONRequestBody PostPullRequestReviewJSONBody
+
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamDeactivateUsersJSONRequestBody defines body for PostTeamDeactivateUsers for application/json ContentType.
+type PostTeamDeactivateUsersJSONRequestBody PostTeamDeactivateUsersJSONBody
+
+// PostTeamSetReviewersRequiredJSONRequestBody defines body for PostTeamSetReviewersRequired for application/json ContentType.
+type PostTeamSetReviewersRequiredJSONRequestBody PostTeamSetReviewersRequiredJSONBody
+
+// PostTeamSetSettingsJSONRequestBody defines body for PostTeamSetSettings for application/json ContentType.
+type PostTeamSetSettingsJSONRequestBody = TeamSettings
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+
+// PostWebhooksAddJSONRequestBody defines body for PostWebhooksAdd for application/json ContentType.
+type PostWebhooksAddJSONRequestBody PostWebhooksAddJSONBody
+
+// PostWebhooksDeleteJSONRequestBody defines body for PostWebhooksDelete for application/json ContentType.
+type PostWebhooksDeleteJSONRequestBody PostWebhooksDeleteJSONBody
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"pr-reviewer/internal/models"
+	"time"
+)
+
+// DefaultActor записывается в журнал PR, если инициатор изменения не передан
+const DefaultActor = "api"
+
+// Причины назначения и замены ревьюверов в журнале PR
+const (
+	ReasonAuto              = "auto"
+	ReasonManual            = "manual"
+	ReasonUserDeactivated   = "user_deactivated"
+	ReasonReviewersRequired = "reviewers_required"
+	ReasonReopened          = "reopened"
+)
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте инициатора изменений для журнала PR
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor возвращает инициатора изменений из контекста или DefaultActor
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
+// newEvent заполняет общие поля события журнала PR
+func newEvent(ctx context.Context, prId string, eventType models.PullRequestEventType, at time.Time) models.PullRequestEvent {
+	return models.PullRequestEvent{
+		PullRequestId: prId,
+		EventType:     eventType,
+		Actor:         Actor(ctx),
+		CreatedAt:     at,
+	}
+}
+
+func assignedEvents(ctx context.Context, prId string, reviewers []string, reason string, at time.Time) []models.PullRequestEvent {
+	events := make([]models.PullRequestEvent, 0, len(reviewers))
+	for _, reviewerId := range reviewers {
+		e := newEvent(ctx, prId, models.ReviewerAssigned, at)
+		e.ReviewerId = &reviewerId
+		e.Reason = &reason
+		events = append(events, e)
+	}
+	return events
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"strings"
+)
+
+var ErrGitHubUserNotMapped = &ServiceError{Code: models.NOTFOUND, Message: "логин GitHub не сопоставлен пользователю"}
+
+// SetGitHubUser сопоставляет логин GitHub существующему пользователю.
+// Логины GitHub не различают регистр, поэтому сохраняются в нижнем регистре.
+func (s *Service) SetGitHubUser(ctx context.Context, login, userId string) error {
+	if login == "" {
+		return ErrGitHubUserNotMapped
+	}
+
+	if _, err := s.storage.GetUser(ctx, userId); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+
+	if err := s.storage.SaveGitHubUser(ctx, strings.ToLower(login), userId); err != nil {
+		return fmt.Errorf("ошибка при сохранении логина GitHub: %w", err)
+	}
+	return nil
+}
+
+// ResolveGitHubUser возвращает user_id, сопоставленный логину GitHub
+func (s *Service) ResolveGitHubUser(ctx context.Context, login string) (string, error) {
+	userId, err := s.storage.GetGitHubUser(ctx, strings.ToLower(login))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrGitHubUserNotMapped
+		}
+		return "", fmt.Errorf("ошибка при получении логина GitHub: %w", err)
+	}
+	return userId, nil
+}
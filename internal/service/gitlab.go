@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+)
+
+var (
+	ErrUsernameNotFound  = &ServiceError{Code: models.NOTFOUND, Message: "пользователь с таким username не найден"}
+	ErrUsernameAmbiguous = &ServiceError{Code: models.NOTFOUND, Message: "username принадлежит нескольким пользователям"}
+)
+
+// ResolveUsername возвращает user_id единственного пользователя с данным username (без учёта регистра).
+// Username не уникален между командами, поэтому неоднозначное совпадение считается ошибкой.
+func (s *Service) ResolveUsername(ctx context.Context, username string) (string, error) {
+	if username == "" {
+		return "", ErrUsernameNotFound
+	}
+
+	users, err := s.storage.GetUsersByUsername(ctx, username)
+	if err != nil {
+		return "", fmt.Errorf("ошибка при поиске пользователя по username: %w", err)
+	}
+	switch len(users) {
+	case 0:
+		return "", ErrUsernameNotFound
+	case 1:
+		return users[0].UserId, nil
+	default:
+		return "", ErrUsernameAmbiguous
+	}
+}
+
+// GetUsernames возвращает username пользователей userIds в том же порядке
+func (s *Service) GetUsernames(ctx context.Context, userIds []string) ([]string, error) {
+	usernames := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		user, err := s.storage.GetUser(ctx, userId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+		}
+		usernames = append(usernames, user.Username)
+	}
+	return usernames, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"time"
+)
+
+// Размер страницы списков PR
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrUnknownStatus = &ServiceError{Code: models.INVALIDREQUEST, Message: "неизвестный статус PR"}
+	ErrInvalidCursor = &ServiceError{Code: models.INVALIDREQUEST, Message: "некорректный курсор"}
+	ErrInvalidLimit  = &ServiceError{Code: models.INVALIDREQUEST, Message: fmt.Sprintf("limit должен быть от 1 до %d", MaxPageLimit)}
+)
+
+// PullRequestQuery — фильтры и страница списка PR; пустые поля не ограничивают выборку
+type PullRequestQuery struct {
+	AuthorId *string
+	// TeamName — команда автора PR
+	TeamName   *string
+	ReviewerId *string
+	Status     *models.PullRequestStatus
+	// NameContains — подстрока названия PR без учёта регистра
+	NameContains *string
+	// CreatedAfter и CreatedBefore задают окно [CreatedAfter, CreatedBefore) по createdAt
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Descending сортирует от новых PR к старым
+	Descending bool
+	// Cursor — next_cursor предыдущей страницы; пустой курсор означает первую страницу
+	Cursor string
+	// Limit — размер страницы; 0 означает DefaultPageLimit
+	Limit int
+}
+
+// pageCursor — содержимое курсора: ключ последнего PR выданной страницы
+type pageCursor struct {
+	CreatedAt     time.Time `json:"created_at"`
+	PullRequestId string    `json:"pull_request_id"`
+}
+
+func encodeCursor(key db.PageKey) string {
+	data, _ := json.Marshal(pageCursor{CreatedAt: key.CreatedAt, PullRequestId: key.PullRequestId})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (*db.PageKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.PullRequestId == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &db.PageKey{CreatedAt: c.CreatedAt.UTC(), PullRequestId: c.PullRequestId}, nil
+}
+
+// utc переводит момент в UTC. Колонки PostgreSQL без часового пояса хранят время в UTC,
+// а pgx при записи отбрасывает смещение, поэтому сравнивать можно только UTC-время.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// filter проверяет query и переводит его в фильтр хранилища.
+// Хранилище возвращает на один PR больше страницы, чтобы page узнал, есть ли следующая.
+func (q PullRequestQuery) filter() (db.PullRequestFilter, error) {
+	if q.Status != nil {
+		switch *q.Status {
+		case models.PullRequestStatusOPEN, models.PullRequestStatusMERGED, models.PullRequestStatusCLOSED:
+		default:
+			return db.PullRequestFilter{}, ErrUnknownStatus
+		}
+	}
+
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return db.PullRequestFilter{}, ErrInvalidLimit
+	}
+
+	filter := db.PullRequestFilter{
+		AuthorId:      q.AuthorId,
+		TeamName:      q.TeamName,
+		ReviewerId:    q.ReviewerId,
+		Status:        q.Status,
+		NameContains:  q.NameContains,
+		CreatedAfter:  utc(q.CreatedAfter),
+		CreatedBefore: utc(q.CreatedBefore),
+		Descending:    q.Descending,
+		Limit:         limit + 1,
+	}
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return db.PullRequestFilter{}, err
+		}
+		filter.After = after
+	}
+	return filter, nil
+}
+
+// page обрезает выборку filter до размера страницы и возвращает курсор следующей страницы;
+// пустой курсор означает, что страница последняя
+func page(pullRequests []models.PullRequest, filter db.PullRequestFilter) ([]models.PullRequest, string) {
+	limit := filter.Limit - 1
+	if len(pullRequests) <= limit {
+		return pullRequests, ""
+	}
+	pullRequests = pullRequests[:limit]
+	return pullRequests, encodeCursor(db.PageKeyOf(&pullRequests[limit-1]))
+}
@@ -0,0 +1,25 @@
+package service
+
+// Recorder получает доменные события сервиса, например для метрик.
+// Методы вызываются после фиксации транзакции и не должны блокироваться.
+type Recorder interface {
+	// PullRequestCreated — создан PR; assigned может быть меньше required, если не хватило кандидатов
+	PullRequestCreated(assigned, required int)
+	// PullRequestReady — черновик помечен готовым и получил assigned ревьюверов из required
+	PullRequestReady(assigned, required int)
+	// PullRequestMerged — PR переведён в MERGED (повторный merge не учитывается)
+	PullRequestMerged()
+	// ReviewerReassigned — ревьювер заменён на другого
+	ReviewerReassigned()
+	// NoCandidate — замена не выполнена, потому что в команде нет кандидатов
+	NoCandidate()
+}
+
+// nopRecorder используется, если Options.Recorder не задан
+type nopRecorder struct{}
+
+func (nopRecorder) PullRequestCreated(int, int) {}
+func (nopRecorder) PullRequestReady(int, int)   {}
+func (nopRecorder) PullRequestMerged()          {}
+func (nopRecorder) ReviewerReassigned()         {}
+func (nopRecorder) NoCandidate()                {}
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"pr-reviewer/internal/db"
 	"pr-reviewer/internal/models"
+	"slices"
 	"time"
 )
 
@@ -22,26 +24,80 @@ var (
 	ErrTeamExists          = &ServiceError{Code: models.TEAMEXISTS, Message: "команда с таким именем уже существует"}
 	ErrTeamNotFound        = &ServiceError{Code: models.NOTFOUND, Message: "команда не найдена"}
 	ErrUserNotFound        = &ServiceError{Code: models.NOTFOUND, Message: "пользователь не найден"}
+	ErrUserNotInTeam       = &ServiceError{Code: models.NOTFOUND, Message: "пользователь не состоит в команде"}
 	ErrPRExists            = &ServiceError{Code: models.PREXISTS, Message: "PR с таким идентификатором уже существует"}
 	ErrPRNotFound          = &ServiceError{Code: models.NOTFOUND, Message: "PR не найден"}
 	ErrPRMerged            = &ServiceError{Code: models.PRMERGED, Message: "нельзя переназначить ревьювера для объединённого PR"}
+	ErrPRAlreadyMerged     = &ServiceError{Code: models.PRMERGED, Message: "PR уже объединён"}
+	ErrPRClosed            = &ServiceError{Code: models.PRCLOSED, Message: "PR закрыт, сначала его нужно открыть заново"}
+	ErrPRDraft             = &ServiceError{Code: models.PRDRAFT, Message: "PR — черновик, сначала его нужно пометить готовым к ревью"}
 	ErrReviewerNotAssigned = &ServiceError{Code: models.NOTASSIGNED, Message: "ревьювер не назначен на этот PR"}
 	ErrNoCandidate         = &ServiceError{Code: models.NOCANDIDATE, Message: "нет активных кандидатов для замены в команде"}
+	ErrNotEnoughApprovals  = &ServiceError{Code: models.NOTENOUGHAPPROVALS, Message: "PR не набрал требуемого числа одобрений"}
+	ErrUnknownVerdict      = &ServiceError{Code: models.INVALIDREQUEST, Message: "неизвестный вердикт ревью"}
+	ErrUnknownStrategy     = &ServiceError{Code: models.INVALIDSETTINGS, Message: "неизвестная стратегия выбора ревьюверов"}
+	ErrInvalidReviewers    = &ServiceError{Code: models.INVALIDSETTINGS, Message: fmt.Sprintf("число ревьюверов должно быть от 1 до %d", MaxReviewerCount)}
+	ErrInvalidApprovals    = &ServiceError{Code: models.INVALIDSETTINGS, Message: fmt.Sprintf("число одобрений должно быть от 0 до %d", MaxReviewerCount)}
+	ErrApprovalsExceed     = &ServiceError{Code: models.INVALIDSETTINGS, Message: "число одобрений не может превышать число ревьюверов команды"}
 )
 
+const (
+	// DefaultReviewerCount — число ревьюверов на новый PR, если ни команда, ни Options его не задают
+	DefaultReviewerCount = 2
+	// MaxReviewerCount — верхняя граница reviewers_required команды
+	MaxReviewerCount = 10
+)
+
+// Options задаёт параметры сервиса; нулевые значения заменяются значениями по умолчанию
+type Options struct {
+	// ReviewerCount — сколько ревьюверов назначается на PR команды, не задавшей reviewers_required
+	ReviewerCount int
+	// Recorder получает доменные события; nil отключает их учёт
+	Recorder Recorder
+}
+
 // Service содержит бизнес-логику
 type Service struct {
-	storage *db.Storage
+	storage       db.Repository
+	strategies    map[models.ReviewerStrategy]ReviewerSelectionStrategy
+	reviewerCount int
+	recorder      Recorder
 }
 
 // NewService создает новый сервис
-func NewService(storage *db.Storage) *Service {
-	return &Service{storage: storage}
+func NewService(storage db.Repository, opts Options) *Service {
+	if opts.ReviewerCount <= 0 {
+		opts.ReviewerCount = DefaultReviewerCount
+	}
+	if opts.Recorder == nil {
+		opts.Recorder = nopRecorder{}
+	}
+
+	return &Service{
+		storage:       storage,
+		reviewerCount: opts.ReviewerCount,
+		recorder:      opts.Recorder,
+		strategies: map[models.ReviewerStrategy]ReviewerSelectionStrategy{
+			models.FirstN:      FirstNStrategy{},
+			models.Random:      NewRandomStrategy(time.Now().UnixNano()),
+			models.RoundRobin:  RoundRobinStrategy{},
+			models.LeastLoaded: LeastLoadedStrategy{},
+		},
+	}
+}
+
+// withStorage возвращает копию сервиса, работающую через storage (например, транзакцию)
+func (s *Service) withStorage(storage db.Repository) *Service {
+	return &Service{storage: storage, strategies: s.strategies, reviewerCount: s.reviewerCount, recorder: s.recorder}
 }
 
 // CreateTeam создает команду с участниками
-func (s *Service) CreateTeam(team *models.Team) error {
-	exists, err := s.storage.TeamExists(team.TeamName)
+func (s *Service) CreateTeam(ctx context.Context, team *models.Team) error {
+	if team.ReviewersRequired != nil && !validReviewerCount(*team.ReviewersRequired) {
+		return ErrInvalidReviewers
+	}
+
+	exists, err := s.storage.TeamExists(ctx, team.TeamName)
 
 	if err != nil {
 		return fmt.Errorf("ошибка при проверке: %w", err)
@@ -51,7 +107,7 @@ func (s *Service) CreateTeam(team *models.Team) error {
 		return ErrTeamExists
 	}
 
-	err = s.storage.SaveTeam(team)
+	err = s.storage.SaveTeam(ctx, team)
 	if err != nil {
 		return fmt.Errorf("ошибка при сохранении команды: %w", err)
 	}
@@ -59,160 +115,1008 @@ func (s *Service) CreateTeam(team *models.Team) error {
 }
 
 // GetTeam получает команду
-func (s *Service) GetTeam(name string) (*models.Team, error) {
-	team, err := s.storage.GetTeam(name)
+func (s *Service) GetTeam(ctx context.Context, name string) (*models.Team, error) {
+	team, err := s.storage.GetTeam(ctx, name)
 	if err != nil {
-		// TODO: надо отличать бизнесовую ошибку от ошибки БД
-		return nil, ErrTeamNotFound
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении команды: %w", err)
 	}
 	return team, nil
 }
 
-// SetUserActive устанавливает флаг активности пользователя
-func (s *Service) SetUserActive(userId string, isActive bool) (*models.User, error) {
-	user, err := s.storage.GetUser(userId)
+// SetTeamReviewersRequired изменяет число ревьюверов, назначаемых на PR команды
+func (s *Service) SetTeamReviewersRequired(ctx context.Context, teamName string, count int) (*models.Team, error) {
+	if !validReviewerCount(count) {
+		return nil, ErrInvalidReviewers
+	}
+
+	var team *models.Team
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		// Иначе ни один PR команды не наберёт нужного числа одобрений
+		settings, err := tx.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении настроек: %w", err)
+		}
+		if *settings.ApprovalsRequired > count {
+			return ErrApprovalsExceed
+		}
+
+		if err := tx.SetTeamReviewersRequired(ctx, teamName, count); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrTeamNotFound
+			}
+			return fmt.Errorf("ошибка при изменении числа ревьюверов: %w", err)
+		}
+
+		team, err = tx.GetTeam(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении команды: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		// TODO: надо отличать бизнесовую ошибку от ошибки БД
-		return nil, ErrUserNotFound
+		return nil, err
 	}
+	return team, nil
+}
 
-	user.IsActive = isActive
-	err = s.storage.SaveUser(user)
+// reviewersRequired возвращает число ревьюверов для PR команды
+func (s *Service) reviewersRequired(team *models.Team) int {
+	if team.ReviewersRequired != nil {
+		return *team.ReviewersRequired
+	}
+	return s.reviewerCount
+}
+
+func validReviewerCount(count int) bool {
+	return count >= 1 && count <= MaxReviewerCount
+}
+
+// GetTeamSettings получает настройки команды
+func (s *Service) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	exists, err := s.storage.TeamExists(ctx, teamName)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при сохранении пользователя: %w", err)
+		return nil, fmt.Errorf("ошибка при проверке: %w", err)
+	}
+	if !exists {
+		return nil, ErrTeamNotFound
 	}
 
-	return user, nil
+	settings, err := s.storage.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении настроек: %w", err)
+	}
+	if settings.ReviewerStrategy == "" {
+		settings.ReviewerStrategy = DefaultReviewerStrategy
+	}
+	return settings, nil
 }
 
-// CreatePullRequest создает PR и автоматически назначает до 2 ревьюверов
-func (s *Service) CreatePullRequest(prId, prName, authorId string) (*models.PullRequest, error) {
-	if _, err := s.storage.PullRequestExists(prId); err != nil {
-		// TODO: надо отличать бизнесовую ошибку от ошибки БД
-		return nil, ErrPRExists
+// SetTeamSettings изменяет настройки команды
+func (s *Service) SetTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error) {
+	if _, ok := s.strategies[settings.ReviewerStrategy]; !ok {
+		return nil, ErrUnknownStrategy
+	}
+	if a := settings.ApprovalsRequired; a != nil && (*a < 0 || *a > MaxReviewerCount) {
+		return nil, ErrInvalidApprovals
 	}
 
-	// TODO: лучше сразу получить команду по authorId, а не два раза ходить в БД
-	author, err := s.storage.GetUser(authorId)
+	team, err := s.GetTeam(ctx, settings.TeamName)
 	if err != nil {
-		// надо отличать бизнесовую ошибку от ошибки БД
-		return nil, ErrUserNotFound
+		return nil, err
+	}
+	if a := settings.ApprovalsRequired; a != nil && *a > s.reviewersRequired(team) {
+		return nil, ErrApprovalsExceed
+	}
+
+	// Не переданное число одобрений сохраняет текущее значение
+	if settings.ApprovalsRequired == nil {
+		current, err := s.storage.GetTeamSettings(ctx, settings.TeamName)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при получении настроек: %w", err)
+		}
+		settings.ApprovalsRequired = current.ApprovalsRequired
+	}
+
+	if err := s.storage.SaveTeamSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("ошибка при сохранении настроек: %w", err)
 	}
+	return settings, nil
+}
 
-	team, err := s.storage.GetTeam(author.TeamName)
+// SetUserActive устанавливает флаг активности пользователя
+func (s *Service) SetUserActive(ctx context.Context, userId string, isActive bool) (*models.User, error) {
+	var user *models.User
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		var err error
+		user, err = tx.GetUser(ctx, userId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("ошибка при получении пользователя: %w", err)
+		}
+
+		deactivated := user.IsActive && !isActive
+		user.IsActive = isActive
+		if err := tx.SaveUser(ctx, user); err != nil {
+			return fmt.Errorf("ошибка при сохранении пользователя: %w", err)
+		}
+
+		if deactivated {
+			return s.withStorage(tx).notify(ctx, models.WebhookPayload{
+				Event:      models.UserDeactivated,
+				OccurredAt: time.Now().UTC(),
+				User:       user,
+			})
+		}
+		return nil
+	})
 	if err != nil {
-		// TODO: надо отличать бизнесовую ошибку от ошибки БД
-		return nil, ErrTeamNotFound
+		return nil, err
 	}
+	return user, nil
+}
+
+// CreatePullRequest создает PR и автоматически назначает до reviewers_required команды автора ревьюверов.
+// Выбор ревьюверов и вставка выполняются в одной транзакции, повторный идентификатор даёт ErrPRExists.
+func (s *Service) CreatePullRequest(ctx context.Context, prId, prName, authorId string) (*models.PullRequest, error) {
+	return s.createPullRequest(ctx, prId, prName, authorId, false)
+}
+
+// CreateDraftPullRequest создает черновик PR без ревьюверов; они назначаются в MarkPullRequestReady
+func (s *Service) CreateDraftPullRequest(ctx context.Context, prId, prName, authorId string) (*models.PullRequest, error) {
+	return s.createPullRequest(ctx, prId, prName, authorId, true)
+}
+
+func (s *Service) createPullRequest(ctx context.Context, prId, prName, authorId string, draft bool) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	var required int
 
-	reviewers := s.findActiveReviewers(team, authorId, 2)
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		exists, err := tx.PullRequestExists(ctx, prId)
+		if err != nil {
+			return fmt.Errorf("ошибка при проверке: %w", err)
+		}
+		if exists {
+			return ErrPRExists
+		}
+
+		team, err := s.withStorage(tx).authorTeam(ctx, authorId)
+		if err != nil {
+			return err
+		}
 
-	now := time.Now()
-	pr := &models.PullRequest{
-		PullRequestId:     prId,
-		PullRequestName:   prName,
-		AuthorId:          authorId,
-		Status:            models.PullRequestStatusOPEN,
-		AssignedReviewers: reviewers,
-		CreatedAt:         &now,
+		// Черновику ревьюверы не нужны, поэтому и не требуются
+		reviewers := []string{}
+		if !draft {
+			required = s.reviewersRequired(team)
+			reviewers, err = s.withStorage(tx).findActiveReviewers(ctx, team, []string{authorId}, required)
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().UTC()
+		pr = &models.PullRequest{
+			PullRequestId:     prId,
+			PullRequestName:   prName,
+			AuthorId:          authorId,
+			Status:            models.PullRequestStatusOPEN,
+			Draft:             draft,
+			AssignedReviewers: reviewers,
+			Reviews:           []models.Review{},
+			CreatedAt:         &now,
+		}
+
+		// Проверка выше не защищает от параллельного создания, поэтому полагаемся на результат вставки
+		created, err := tx.CreatePullRequest(ctx, pr)
+		if err != nil {
+			return fmt.Errorf("ошибка при сохранении PR: %w", err)
+		}
+		if !created {
+			return ErrPRExists
+		}
+
+		events := append(
+			[]models.PullRequestEvent{newEvent(ctx, prId, models.Created, now)},
+			assignedEvents(ctx, prId, reviewers, ReasonAuto, now)...,
+		)
+		if err := tx.AppendPullRequestEvents(ctx, events); err != nil {
+			return fmt.Errorf("ошибка при записи журнала PR: %w", err)
+		}
+
+		return s.withStorage(tx).notify(ctx, models.WebhookPayload{
+			Event:       models.PullRequestCreated,
+			OccurredAt:  now,
+			PullRequest: pr,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.storage.SavePullRequest(pr)
+	s.recorder.PullRequestCreated(len(pr.AssignedReviewers), required)
+	return pr, nil
+}
+
+// MarkPullRequestReady снимает с PR признак черновика и назначает ревьюверов так же, как CreatePullRequest.
+// Для PR, который не является черновиком, ничего не меняет; закрытый черновик даёт ErrPRClosed.
+func (s *Service) MarkPullRequestReady(ctx context.Context, prId string) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	var required int
+	var ready bool
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		var err error
+		pr, err = tx.GetPullRequestForUpdate(ctx, prId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrPRNotFound
+			}
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		// Идемпотентная операция
+		if !pr.Draft {
+			return nil
+		}
+		if pr.Status == models.PullRequestStatusCLOSED {
+			return ErrPRClosed
+		}
+
+		txService := s.withStorage(tx)
+		team, err := txService.authorTeam(ctx, pr.AuthorId)
+		if err != nil {
+			return err
+		}
+		required = s.reviewersRequired(team)
+		reviewers, err := txService.findActiveReviewers(ctx, team, []string{pr.AuthorId}, required)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		pr.Draft = false
+		pr.AssignedReviewers = reviewers
+		if err := tx.SavePullRequest(ctx, pr); err != nil {
+			return fmt.Errorf("ошибка при сохранении PR: %w", err)
+		}
+
+		events := append(
+			[]models.PullRequestEvent{newEvent(ctx, prId, models.ReadyForReview, now)},
+			assignedEvents(ctx, prId, reviewers, ReasonAuto, now)...,
+		)
+		if err := tx.AppendPullRequestEvents(ctx, events); err != nil {
+			return fmt.Errorf("ошибка при записи журнала PR: %w", err)
+		}
+		ready = true
+
+		return txService.notify(ctx, models.WebhookPayload{
+			Event:       models.PullRequestReadyForReview,
+			OccurredAt:  now,
+			PullRequest: pr,
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при сохранении PR: %w", err)
+		return nil, err
+	}
+
+	if ready {
+		s.recorder.PullRequestReady(len(pr.AssignedReviewers), required)
 	}
 	return pr, nil
 }
 
+// authorTeam возвращает команду автора PR
+func (s *Service) authorTeam(ctx context.Context, authorId string) (*models.Team, error) {
+	// TODO: лучше сразу получить команду по authorId, а не два раза ходить в БД
+	author, err := s.storage.GetUser(ctx, authorId)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении автора: %w", err)
+	}
+
+	team, err := s.storage.GetTeam(ctx, author.TeamName)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении команды: %w", err)
+	}
+	return team, nil
+}
+
 // MergePullRequest помечает PR как MERGED
-func (s *Service) MergePullRequest(prId string) (*models.PullRequest, error) {
-	pr, exists := s.storage.GetPullRequest(prId)
-	if !exists {
-		return nil, ErrPRNotFound
+func (s *Service) MergePullRequest(ctx context.Context, prId string) (*models.PullRequest, error) {
+	return s.merge(ctx, prId, false)
+}
+
+// RecordExternalMerge фиксирует merge, уже выполненный в GitHub или GitLab.
+// Проверки черновика и одобрений пропускаются: отменить внешний merge они не могут,
+// а PR иначе навсегда остался бы открытым в очередях ревьюверов.
+func (s *Service) RecordExternalMerge(ctx context.Context, prId string) (*models.PullRequest, error) {
+	return s.merge(ctx, prId, true)
+}
+
+// merge помечает PR как MERGED; external отключает проверки черновика и одобрений
+func (s *Service) merge(ctx context.Context, prId string, external bool) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	var merged bool
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		var err error
+		pr, err = tx.GetPullRequestForUpdate(ctx, prId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrPRNotFound
+			}
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		// Идемпотентная операция
+		if pr.Status == models.PullRequestStatusMERGED {
+			return nil
+		}
+		if pr.Status == models.PullRequestStatusCLOSED {
+			return ErrPRClosed
+		}
+		if !external {
+			if pr.Draft {
+				return ErrPRDraft
+			}
+			if err := s.withStorage(tx).checkApprovals(ctx, pr); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().UTC()
+		pr.Draft = false
+		pr.Status = models.PullRequestStatusMERGED
+		pr.MergedAt = &now
+		if err := tx.SavePullRequest(ctx, pr); err != nil {
+			return fmt.Errorf("ошибка при сохранении PR: %w", err)
+		}
+
+		if err := tx.AppendPullRequestEvents(ctx, []models.PullRequestEvent{newEvent(ctx, prId, models.Merged, now)}); err != nil {
+			return fmt.Errorf("ошибка при записи журнала PR: %w", err)
+		}
+		merged = true
+
+		return s.withStorage(tx).notify(ctx, models.WebhookPayload{
+			Event:       models.PullRequestMerged,
+			OccurredAt:  now,
+			PullRequest: pr,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Идемпотентная операция
-	if pr.Status == models.PullRequestStatusMERGED {
-		return pr, nil
+	if merged {
+		s.recorder.PullRequestMerged()
 	}
+	return pr, nil
+}
 
-	now := time.Now()
-	pr.Status = models.PullRequestStatusMERGED
-	pr.MergedAt = &now
-	err := s.storage.SavePullRequest(pr)
+// checkApprovals проверяет, что PR одобрили не меньше approvals_required команды автора ревьюверов.
+// Если активных кандидатов не хватило и назначено меньше ревьюверов, достаточно одобрения каждого из них,
+// иначе такой PR нельзя было бы объединить.
+func (s *Service) checkApprovals(ctx context.Context, pr *models.PullRequest) error {
+	author, err := s.storage.GetUser(ctx, pr.AuthorId)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при сохранении PR: %w", err)
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("ошибка при получении автора: %w", err)
+	}
+
+	settings, err := s.storage.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении настроек: %w", err)
+	}
+	required := min(*settings.ApprovalsRequired, len(pr.AssignedReviewers))
+	if required == 0 {
+		return nil
+	}
+
+	approvals := 0
+	for _, review := range pr.Reviews {
+		if review.Verdict == models.APPROVED {
+			approvals++
+		}
+	}
+	if approvals < required {
+		return ErrNotEnoughApprovals
+	}
+	return nil
+}
+
+// ReviewPullRequest сохраняет вердикт назначенного ревьювера; повторный вердикт заменяет предыдущий
+func (s *Service) ReviewPullRequest(ctx context.Context, prId, reviewerId string, verdict models.ReviewVerdict) (*models.PullRequest, error) {
+	switch verdict {
+	case models.APPROVED, models.CHANGESREQUESTED, models.COMMENTED:
+	default:
+		return nil, ErrUnknownVerdict
 	}
 
+	var pr *models.PullRequest
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		var err error
+		pr, err = tx.GetPullRequestForUpdate(ctx, prId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrPRNotFound
+			}
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		switch {
+		case pr.Status == models.PullRequestStatusMERGED:
+			return ErrPRAlreadyMerged
+		case pr.Status == models.PullRequestStatusCLOSED:
+			return ErrPRClosed
+		case pr.Draft:
+			return ErrPRDraft
+		case !slices.Contains(pr.AssignedReviewers, reviewerId):
+			return ErrReviewerNotAssigned
+		}
+
+		now := time.Now().UTC()
+		review := models.Review{ReviewerId: reviewerId, Verdict: verdict, SubmittedAt: now}
+		if err := tx.SaveReview(ctx, prId, review); err != nil {
+			return fmt.Errorf("ошибка при сохранении вердикта: %w", err)
+		}
+		// Перечитываем PR, чтобы вердикты шли в порядке слотов, как при последующих чтениях
+		pr, err = tx.GetPullRequest(ctx, prId)
+		if err != nil {
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		reviewed := newEvent(ctx, prId, models.Reviewed, now)
+		reviewed.ReviewerId = &reviewerId
+		reviewed.Verdict = &verdict
+		if err := tx.AppendPullRequestEvents(ctx, []models.PullRequestEvent{reviewed}); err != nil {
+			return fmt.Errorf("ошибка при записи журнала PR: %w", err)
+		}
+
+		return s.withStorage(tx).notify(ctx, models.WebhookPayload{
+			Event:       models.PullRequestReviewed,
+			OccurredAt:  now,
+			PullRequest: pr,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 	return pr, nil
 }
 
-// ReassignReviewer переназначает ревьювера
-// TODO: убрать newReviewerId
-func (s *Service) ReassignReviewer(prId, oldReviewerId, newReviewerId string) (*models.PullRequest, error) {
-	pr, exists := s.storage.GetPullRequest(prId)
-	if !exists {
-		return nil, ErrPRNotFound
+// ClosePullRequest закрывает открытый PR без merge; повторное закрытие ничего не меняет
+func (s *Service) ClosePullRequest(ctx context.Context, prId string) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		var err error
+		pr, err = tx.GetPullRequestForUpdate(ctx, prId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrPRNotFound
+			}
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		switch pr.Status {
+		case models.PullRequestStatusCLOSED:
+			return nil
+		case models.PullRequestStatusMERGED:
+			return ErrPRAlreadyMerged
+		}
+
+		now := time.Now().UTC()
+		pr.Status = models.PullRequestStatusCLOSED
+		pr.ClosedAt = &now
+		if err := tx.SavePullRequest(ctx, pr); err != nil {
+			return fmt.Errorf("ошибка при сохранении PR: %w", err)
+		}
+
+		if err := tx.AppendPullRequestEvents(ctx, []models.PullRequestEvent{newEvent(ctx, prId, models.Closed, now)}); err != nil {
+			return fmt.Errorf("ошибка при записи журнала PR: %w", err)
+		}
+
+		return s.withStorage(tx).notify(ctx, models.WebhookPayload{
+			Event:       models.PullRequestClosed,
+			OccurredAt:  now,
+			PullRequest: pr,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// ReopenPullRequest открывает закрытый PR заново; для открытого PR ничего не меняет.
+// Ревьюверы, ставшие неактивными, пока PR был закрыт, заменяются так же, как при деактивации;
+// ревьювер, которому не нашлось замены, остаётся назначенным.
+// Вебхук pull_request.reopened ставится в очередь после замен и содержит итоговых ревьюверов.
+func (s *Service) ReopenPullRequest(ctx context.Context, prId string) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	var reassigned, noCandidate int
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		txService := s.withStorage(tx)
+
+		var err error
+		pr, err = tx.GetPullRequestForUpdate(ctx, prId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrPRNotFound
+			}
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		switch pr.Status {
+		case models.PullRequestStatusOPEN:
+			return nil
+		case models.PullRequestStatusMERGED:
+			return ErrPRAlreadyMerged
+		}
+
+		now := time.Now().UTC()
+		pr.Status = models.PullRequestStatusOPEN
+		pr.ClosedAt = nil
+		if err := tx.SavePullRequest(ctx, pr); err != nil {
+			return fmt.Errorf("ошибка при сохранении PR: %w", err)
+		}
+
+		if err := tx.AppendPullRequestEvents(ctx, []models.PullRequestEvent{newEvent(ctx, prId, models.Reopened, now)}); err != nil {
+			return fmt.Errorf("ошибка при записи журнала PR: %w", err)
+		}
+
+		// reassign меняет pr.AssignedReviewers, поэтому обходим копию
+		for _, reviewerId := range slices.Clone(pr.AssignedReviewers) {
+			reviewer, err := tx.GetUser(ctx, reviewerId)
+			if err != nil {
+				return fmt.Errorf("ошибка при получении ревьювера: %w", err)
+			}
+			if reviewer.IsActive {
+				continue
+			}
+
+			_, err = txService.reassign(ctx, pr, reviewerId, ReasonReopened)
+			if errors.Is(err, ErrNoCandidate) {
+				noCandidate++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			reassigned++
+		}
+
+		return txService.notify(ctx, models.WebhookPayload{
+			Event:       models.PullRequestReopened,
+			OccurredAt:  now,
+			PullRequest: pr,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if pr.Status == models.PullRequestStatusMERGED {
-		return nil, ErrPRMerged
+	for range reassigned {
+		s.recorder.ReviewerReassigned()
+	}
+	for range noCandidate {
+		s.recorder.NoCandidate()
+	}
+	return pr, nil
+}
+
+// ReassignReviewer заменяет ревьювера на другого активного участника его команды.
+// Возвращает обновлённый PR и user_id нового ревьювера.
+func (s *Service) ReassignReviewer(ctx context.Context, prId, oldReviewerId string) (*models.PullRequest, string, error) {
+	var pr *models.PullRequest
+	var newReviewerId string
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		var err error
+		pr, err = tx.GetPullRequestForUpdate(ctx, prId)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return ErrPRNotFound
+			}
+			return fmt.Errorf("ошибка при получении PR: %w", err)
+		}
+
+		newReviewerId, err = s.withStorage(tx).reassign(ctx, pr, oldReviewerId, ReasonManual)
+		return err
+	})
+	if errors.Is(err, ErrNoCandidate) {
+		s.recorder.NoCandidate()
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.recorder.ReviewerReassigned()
+	return pr, newReviewerId, nil
+}
+
+// DeactivateTeamUsers в одной транзакции деактивирует участников команды
+// и переназначает открытые PR, где они ревьюверы, на оставшихся активных участников
+func (s *Service) DeactivateTeamUsers(ctx context.Context, teamName string, userIds []string) (*models.DeactivationReport, error) {
+	report := &models.DeactivationReport{
+		TeamName:         teamName,
+		DeactivatedUsers: []string{},
+		Reassigned:       []models.Reassignment{},
+		NotReassigned:    []models.FailedReassignment{},
+	}
+
+	err := s.storage.InTx(ctx, func(tx db.Repository) error {
+		txService := s.withStorage(tx)
+
+		exists, err := tx.TeamExists(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("ошибка при проверке: %w", err)
+		}
+		if !exists {
+			return ErrTeamNotFound
+		}
+
+		// Сначала деактивируем всех, чтобы они не стали кандидатами на замену друг друга
+		for _, userId := range userIds {
+			if slices.Contains(report.DeactivatedUsers, userId) {
+				continue
+			}
+
+			user, err := tx.GetUser(ctx, userId)
+			if err != nil {
+				if errors.Is(err, db.ErrNotFound) {
+					return ErrUserNotFound
+				}
+				return fmt.Errorf("ошибка при получении пользователя: %w", err)
+			}
+			if user.TeamName != teamName {
+				return ErrUserNotInTeam
+			}
+
+			wasActive := user.IsActive
+			user.IsActive = false
+			if err := tx.SaveUser(ctx, user); err != nil {
+				return fmt.Errorf("ошибка при сохранении пользователя: %w", err)
+			}
+			report.DeactivatedUsers = append(report.DeactivatedUsers, userId)
+
+			if wasActive {
+				err := txService.notify(ctx, models.WebhookPayload{
+					Event:      models.UserDeactivated,
+					OccurredAt: time.Now().UTC(),
+					User:       user,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, userId := range report.DeactivatedUsers {
+			assignedPRs, err := tx.GetPullRequestsByReviewer(ctx, userId)
+			if err != nil {
+				return fmt.Errorf("ошибка при получении PR ревьювера: %w", err)
+			}
+
+			for _, assigned := range assignedPRs {
+				if assigned.Status != models.PullRequestStatusOPEN {
+					continue
+				}
+
+				pr, err := tx.GetPullRequestForUpdate(ctx, assigned.PullRequestId)
+				if err != nil {
+					return fmt.Errorf("ошибка при получении PR: %w", err)
+				}
+
+				newReviewerId, err := txService.reassign(ctx, pr, userId, ReasonUserDeactivated)
+				if errors.Is(err, ErrNoCandidate) {
+					report.NotReassigned = append(report.NotReassigned, models.FailedReassignment{
+						PullRequestId: pr.PullRequestId,
+						UserId:        userId,
+						Reason:        string(ErrNoCandidate.Code),
+					})
+					continue
+				}
+				if err != nil {
+					return err
+				}
+
+				report.Reassigned = append(report.Reassigned, models.Reassignment{
+					PullRequestId: pr.PullRequestId,
+					OldUserId:     userId,
+					NewUserId:     newReviewerId,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for range report.Reassigned {
+		s.recorder.ReviewerReassigned()
+	}
+	for range report.NotReassigned {
+		s.recorder.NoCandidate()
+	}
+	return report, nil
+}
+
+// reassign заменяет oldReviewerId в pr на нового ревьювера из его команды и сохраняет PR,
+// при необходимости добирая ревьюверов до reviewers_required команды.
+// Вызывается внутри транзакции, чтобы PR, запись о замене и журнал сохранялись вместе;
+// reason попадает в событие reviewer_replaced.
+func (s *Service) reassign(ctx context.Context, pr *models.PullRequest, oldReviewerId, reason string) (string, error) {
+	switch pr.Status {
+	case models.PullRequestStatusMERGED:
+		return "", ErrPRMerged
+	case models.PullRequestStatusCLOSED:
+		return "", ErrPRClosed
+	}
+	if pr.Draft {
+		return "", ErrPRDraft
 	}
 
-	// Ищем и заменяем ревьювера
-	found := false
+	slot := -1
 	for i, reviewerId := range pr.AssignedReviewers {
 		if reviewerId == oldReviewerId {
-			pr.AssignedReviewers[i] = newReviewerId
-			found = true
+			slot = i
 			break
 		}
 	}
 
-	if !found {
-		return nil, ErrReviewerNotAssigned
+	if slot < 0 {
+		return "", ErrReviewerNotAssigned
+	}
+
+	oldReviewer, err := s.storage.GetUser(ctx, oldReviewerId)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("ошибка при получении ревьювера: %w", err)
+	}
+
+	team, err := s.storage.GetTeam(ctx, oldReviewer.TeamName)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return "", ErrTeamNotFound
+		}
+		return "", fmt.Errorf("ошибка при получении команды: %w", err)
+	}
+
+	// Исключаем автора и всех уже назначенных, включая заменяемого
+	exclude := append([]string{pr.AuthorId}, pr.AssignedReviewers...)
+	candidates, err := s.findActiveReviewers(ctx, team, exclude, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", ErrNoCandidate
+	}
+
+	newReviewerId := candidates[0]
+	pr.AssignedReviewers[slot] = newReviewerId
+	// Вердикт снятого ревьювера удаляется вместе с назначением
+	pr.Reviews = slices.DeleteFunc(slices.Clone(pr.Reviews), func(r models.Review) bool {
+		return r.ReviewerId == oldReviewerId
+	})
+
+	// Если ревьюверов меньше, чем требует команда (например, требование повысили), добираем недостающих
+	var extra []string
+	if missing := s.reviewersRequired(team) - len(pr.AssignedReviewers); missing > 0 {
+		extra, err = s.findActiveReviewers(ctx, team, append(exclude, newReviewerId), missing)
+		if err != nil {
+			return "", err
+		}
+		pr.AssignedReviewers = append(pr.AssignedReviewers, extra...)
+	}
+
+	err = s.storage.SavePullRequest(ctx, pr)
+	if err != nil {
+		return "", fmt.Errorf("ошибка при сохранении PR: %w", err)
+	}
+
+	now := time.Now().UTC()
+	err = s.storage.SaveReassignment(ctx, pr.PullRequestId, oldReviewerId, newReviewerId, now)
+	if err != nil {
+		return "", fmt.Errorf("ошибка при сохранении переназначения: %w", err)
+	}
+
+	replaced := newEvent(ctx, pr.PullRequestId, models.ReviewerReplaced, now)
+	replaced.OldReviewerId = &oldReviewerId
+	replaced.NewReviewerId = &newReviewerId
+	replaced.Reason = &reason
+	events := append([]models.PullRequestEvent{replaced}, assignedEvents(ctx, pr.PullRequestId, extra, ReasonReviewersRequired, now)...)
+	if err := s.storage.AppendPullRequestEvents(ctx, events); err != nil {
+		return "", fmt.Errorf("ошибка при записи журнала PR: %w", err)
 	}
 
-	err := s.storage.SavePullRequest(pr)
+	err = s.notify(ctx, models.WebhookPayload{
+		Event:         models.PullRequestReassigned,
+		OccurredAt:    now,
+		PullRequest:   pr,
+		OldReviewerId: &oldReviewerId,
+		NewReviewerId: &newReviewerId,
+		Reason:        &reason,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при сохранении PR: %w", err)
+		return "", err
+	}
+	return newReviewerId, nil
+}
+
+// GetPullRequest возвращает PR по идентификатору
+func (s *Service) GetPullRequest(ctx context.Context, prId string) (*models.PullRequest, error) {
+	pr, err := s.storage.GetPullRequest(ctx, prId)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, ErrPRNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении PR: %w", err)
 	}
 	return pr, nil
 }
 
-// GetUserPullRequests получает PR'ы, где пользователь назначен ревьювером
-func (s *Service) GetUserPullRequests(userId string) []models.PullRequestShort {
-	var result []models.PullRequestShort
+// ListPullRequests возвращает страницу PR, подходящих под query,
+// и курсор следующей страницы (пустой, если страница последняя)
+func (s *Service) ListPullRequests(ctx context.Context, query PullRequestQuery) ([]models.PullRequest, string, error) {
+	filter, err := query.filter()
+	if err != nil {
+		return nil, "", err
+	}
+
+	pullRequests, err := s.storage.ListPullRequests(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка при получении списка PR: %w", err)
+	}
+	pullRequests, next := page(pullRequests, filter)
+	return pullRequests, next, nil
+}
+
+// GetPullRequestHistory возвращает журнал изменений PR в порядке записи
+func (s *Service) GetPullRequestHistory(ctx context.Context, prId string) ([]models.PullRequestEvent, error) {
+	exists, err := s.storage.PullRequestExists(ctx, prId)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при проверке: %w", err)
+	}
+	if !exists {
+		return nil, ErrPRNotFound
+	}
 
-	for _, pr := range s.storage.GetPullRequestsByReviewer(userId) {
+	events, err := s.storage.GetPullRequestEvents(ctx, prId)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении журнала PR: %w", err)
+	}
+	return events, nil
+}
+
+// GetUserPullRequests получает страницу PR'ов, где пользователь назначен ревьювером,
+// и курсор следующей страницы (пустой, если страница последняя)
+func (s *Service) GetUserPullRequests(ctx context.Context, userId string, query PullRequestQuery) ([]models.PullRequestShort, string, error) {
+	query.ReviewerId = &userId
+	filter, err := query.filter()
+	if err != nil {
+		return nil, "", err
+	}
+
+	pullRequests, err := s.storage.ListPullRequests(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("ошибка при получении PR ревьювера: %w", err)
+	}
+	pullRequests, next := page(pullRequests, filter)
+
+	result := []models.PullRequestShort{}
+	for _, pr := range pullRequests {
 		result = append(result, models.PullRequestShort{
 			PullRequestId:   pr.PullRequestId,
 			PullRequestName: pr.PullRequestName,
 			AuthorId:        pr.AuthorId,
 			Status:          models.PullRequestShortStatus(pr.Status),
+			CreatedAt:       pr.CreatedAt,
 		})
 	}
 
-	return result
+	return result, next, nil
 }
 
-// findActiveReviewers находит активных ревьюверов из команды (исключая автора)
-func (s *Service) findActiveReviewers(team *models.Team, excludeUserId string, maxCount int) []string {
-	var reviewers []string
+// GetReviewerStats возвращает статистику назначений по ревьюверам
+func (s *Service) GetReviewerStats(ctx context.Context, from, to *time.Time, teamName *string) ([]models.ReviewerStats, error) {
+	stats, err := s.storage.GetReviewerStats(ctx, utc(from), utc(to), teamName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении статистики: %w", err)
+	}
+	return stats, nil
+}
+
+// GetTeamStats возвращает статистику назначений по командам как сумму по их участникам
+func (s *Service) GetTeamStats(ctx context.Context, from, to *time.Time) ([]models.TeamStats, error) {
+	reviewers, err := s.storage.GetReviewerStats(ctx, utc(from), utc(to), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении статистики: %w", err)
+	}
 
+	// Статистика пользователей отсортирована по команде, поэтому команды идут подряд
+	teams := []models.TeamStats{}
+	for _, r := range reviewers {
+		if len(teams) == 0 || teams[len(teams)-1].TeamName != r.TeamName {
+			teams = append(teams, models.TeamStats{TeamName: r.TeamName})
+		}
+
+		team := &teams[len(teams)-1]
+		team.Members++
+		team.Assignments += r.Assignments
+		team.OpenAssignments += r.OpenAssignments
+		team.MergedReviewed += r.MergedReviewed
+		team.ReassignedAway += r.ReassignedAway
+	}
+
+	return teams, nil
+}
+
+// CheckReadiness проверяет доступность хранилища и возвращает версию применённых миграций
+func (s *Service) CheckReadiness(ctx context.Context) (int64, error) {
+	if err := s.storage.Ping(ctx); err != nil {
+		return 0, fmt.Errorf("хранилище недоступно: %w", err)
+	}
+
+	version, err := s.storage.MigrationVersion(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при получении версии миграций: %w", err)
+	}
+	return version, nil
+}
+
+// findActiveReviewers находит активных ревьюверов из команды (исключая exclude)
+// по стратегии, выбранной командой
+func (s *Service) findActiveReviewers(ctx context.Context, team *models.Team, exclude []string, maxCount int) ([]string, error) {
+	var candidates []string
 	for _, member := range team.Members {
-		if member.UserId != excludeUserId {
-			// TODO: зачем снова идти в бд?
-			if member.IsActive {
-				reviewers = append(reviewers, member.UserId)
-				if len(reviewers) >= maxCount {
-					break
-				}
-			}
+		if member.IsActive && !slices.Contains(exclude, member.UserId) {
+			candidates = append(candidates, member.UserId)
 		}
 	}
 
-	return reviewers
+	// Пустой срез, а не nil: список уходит в ответ и вебхук как assigned_reviewers
+	if len(candidates) == 0 || maxCount <= 0 {
+		return []string{}, nil
+	}
+
+	strategy, err := s.strategyFor(ctx, team.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return strategy.Select(ctx, s.storage, team.TeamName, candidates, maxCount)
+}
+
+// strategyFor возвращает стратегию выбора ревьюверов команды
+func (s *Service) strategyFor(ctx context.Context, teamName string) (ReviewerSelectionStrategy, error) {
+	settings, err := s.storage.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении настроек команды: %w", err)
+	}
+
+	if strategy, ok := s.strategies[settings.ReviewerStrategy]; ok {
+		return strategy, nil
+	}
+	return s.strategies[DefaultReviewerStrategy], nil
 }
 
 // IsServiceError проверяет, является ли ошибка ServiceError
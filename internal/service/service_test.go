@@ -0,0 +1,1154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestService создаёт сервис поверх хранилища в памяти с командой из переданных участников
+func newTestService(t *testing.T, teamName string, members ...models.TeamMember) (*Service, *db.MemoryStorage) {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := db.NewMemoryStorage()
+	svc := NewService(storage, Options{})
+	if err := svc.CreateTeam(ctx, &models.Team{TeamName: teamName, Members: members}); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	return svc, storage
+}
+
+func member(id string, active bool) models.TeamMember {
+	return models.TeamMember{UserId: id, Username: id, IsActive: active}
+}
+
+func TestCreatePullRequestAssignsLeastLoaded(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true), member("off", false))
+
+	first, err := svc.CreatePullRequest(ctx, "pr-1", "First", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if len(first.AssignedReviewers) != 2 {
+		t.Fatalf("Ожидалось 2 ревьювера, назначено %v", first.AssignedReviewers)
+	}
+
+	// Третий участник свободен, поэтому должен попасть во второй PR
+	second, err := svc.CreatePullRequest(ctx, "pr-2", "Second", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	var idle string
+	for _, id := range []string{"u1", "u2", "u3"} {
+		if !slices.Contains(first.AssignedReviewers, id) {
+			idle = id
+		}
+	}
+	if !slices.Contains(second.AssignedReviewers, idle) {
+		t.Fatalf("Наименее загруженный %s не назначен: %v", idle, second.AssignedReviewers)
+	}
+
+	for _, pr := range []*models.PullRequest{first, second} {
+		if slices.Contains(pr.AssignedReviewers, "author") || slices.Contains(pr.AssignedReviewers, "off") {
+			t.Fatalf("Назначен автор или неактивный пользователь: %v", pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestCreatePullRequestWithoutCandidates(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("author", true), member("u1", false))
+
+	for _, create := range []func() (*models.PullRequest, error){
+		func() (*models.PullRequest, error) { return svc.CreatePullRequest(ctx, "pr-1", "PR", "author") },
+		func() (*models.PullRequest, error) {
+			if _, err := svc.CreateDraftPullRequest(ctx, "pr-2", "PR", "author"); err != nil {
+				return nil, err
+			}
+			return svc.MarkPullRequestReady(ctx, "pr-2")
+		},
+	} {
+		pr, err := create()
+		if err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+		// В ответе и вебхуке должен быть пустой массив, а не null
+		if pr.AssignedReviewers == nil || len(pr.AssignedReviewers) != 0 {
+			t.Fatalf("Ожидался пустой список ревьюверов, получен %#v", pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestRoundRobinStrategyRotates(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true))
+
+	if _, err := svc.SetTeamSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerStrategy: models.RoundRobin}); err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+
+	want := [][]string{{"u1", "u2"}, {"u3", "u1"}, {"u2", "u3"}}
+	for i, expected := range want {
+		pr, err := svc.CreatePullRequest(ctx, string(rune('a'+i)), "PR", "author")
+		if err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+		if !slices.Equal(pr.AssignedReviewers, expected) {
+			t.Fatalf("PR %d: ожидались %v, назначены %v", i, expected, pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestSetTeamSettingsRejectsUnknownStrategy(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("u1", true))
+
+	_, err := svc.SetTeamSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerStrategy: "alphabetical"})
+	if !errors.Is(err, ErrUnknownStrategy) {
+		t.Fatalf("Ожидалась ошибка ErrUnknownStrategy, получена %v", err)
+	}
+}
+
+func TestTeamReviewersRequired(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true), member("u4", true))
+
+	if _, err := svc.SetTeamReviewersRequired(ctx, "backend", MaxReviewerCount+1); !errors.Is(err, ErrInvalidReviewers) {
+		t.Fatalf("Ожидалась ошибка ErrInvalidReviewers, получена %v", err)
+	}
+	if _, err := svc.SetTeamReviewersRequired(ctx, "frontend", 1); !errors.Is(err, ErrTeamNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrTeamNotFound, получена %v", err)
+	}
+
+	team, err := svc.SetTeamReviewersRequired(ctx, "backend", 1)
+	if err != nil {
+		t.Fatalf("Ошибка изменения числа ревьюверов: %v", err)
+	}
+	if team.ReviewersRequired == nil || *team.ReviewersRequired != 1 {
+		t.Fatalf("Число ревьюверов не сохранено: %v", team.ReviewersRequired)
+	}
+
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 1 {
+		t.Fatalf("Ожидался 1 ревьювер, назначено %v", pr.AssignedReviewers)
+	}
+
+	// После повышения требования переназначение добирает недостающих ревьюверов
+	if _, err := svc.SetTeamReviewersRequired(ctx, "backend", 3); err != nil {
+		t.Fatalf("Ошибка изменения числа ревьюверов: %v", err)
+	}
+	old := pr.AssignedReviewers[0]
+	updated, replacedBy, err := svc.ReassignReviewer(ctx, "pr-1", old)
+	if err != nil {
+		t.Fatalf("Ошибка переназначения: %v", err)
+	}
+	if len(updated.AssignedReviewers) != 3 || updated.AssignedReviewers[0] != replacedBy || slices.Contains(updated.AssignedReviewers, old) {
+		t.Fatalf("Ожидалось 3 ревьювера с заменой %s на первом месте, назначены %v", replacedBy, updated.AssignedReviewers)
+	}
+
+	second, err := svc.CreatePullRequest(ctx, "pr-2", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if len(second.AssignedReviewers) != 3 {
+		t.Fatalf("Ожидалось 3 ревьювера, назначено %v", second.AssignedReviewers)
+	}
+}
+
+func TestServiceReviewerCountOption(t *testing.T) {
+	ctx := context.Background()
+	storage := db.NewMemoryStorage()
+	svc := NewService(storage, Options{ReviewerCount: 1})
+
+	team := &models.Team{TeamName: "backend", Members: []models.TeamMember{member("author", true), member("u1", true), member("u2", true)}}
+	if err := svc.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 1 {
+		t.Fatalf("Ожидался 1 ревьювер, назначено %v", pr.AssignedReviewers)
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", false))
+
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	// Кроме назначенных, кандидатов нет
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", pr.AssignedReviewers[0]); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("Ожидалась ошибка ErrNoCandidate, получена %v", err)
+	}
+
+	if _, err := svc.SetUserActive(ctx, "u3", true); err != nil {
+		t.Fatalf("Ошибка активации: %v", err)
+	}
+
+	old := pr.AssignedReviewers[0]
+	updated, replacedBy, err := svc.ReassignReviewer(ctx, "pr-1", old)
+	if err != nil {
+		t.Fatalf("Ошибка переназначения: %v", err)
+	}
+	if replacedBy != "u3" {
+		t.Fatalf("Ожидалась замена на u3, получено %s", replacedBy)
+	}
+	if updated.AssignedReviewers[0] != "u3" || slices.Contains(updated.AssignedReviewers, old) {
+		t.Fatalf("Ревьювер не заменён на своём месте: %v", updated.AssignedReviewers)
+	}
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "author"); !errors.Is(err, ErrReviewerNotAssigned) {
+		t.Fatalf("Ожидалась ошибка ErrReviewerNotAssigned, получена %v", err)
+	}
+
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Ошибка merge: %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u3"); !errors.Is(err, ErrPRMerged) {
+		t.Fatalf("Ожидалась ошибка ErrPRMerged, получена %v", err)
+	}
+}
+
+func TestCloseAndReopenPullRequest(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true))
+
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	first, second := pr.AssignedReviewers[0], pr.AssignedReviewers[1]
+
+	closed, err := svc.ClosePullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка закрытия: %v", err)
+	}
+	if closed.Status != models.PullRequestStatusCLOSED || closed.ClosedAt == nil {
+		t.Fatalf("PR не закрыт: %+v", closed)
+	}
+	// Повторное закрытие ничего не меняет
+	again, err := svc.ClosePullRequest(ctx, "pr-1")
+	if err != nil || !again.ClosedAt.Equal(*closed.ClosedAt) {
+		t.Fatalf("Повторное закрытие изменило PR: %+v (%v)", again, err)
+	}
+
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); !errors.Is(err, ErrPRClosed) {
+		t.Fatalf("Ожидалась ошибка ErrPRClosed при merge, получена %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", first); !errors.Is(err, ErrPRClosed) {
+		t.Fatalf("Ожидалась ошибка ErrPRClosed при переназначении, получена %v", err)
+	}
+
+	// Пока PR закрыт, ревьювер деактивирован: при открытии его заменяет свободный участник
+	if _, err := svc.SetUserActive(ctx, first, false); err != nil {
+		t.Fatalf("Ошибка деактивации: %v", err)
+	}
+	reopened, err := svc.ReopenPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка открытия: %v", err)
+	}
+	if reopened.Status != models.PullRequestStatusOPEN || reopened.ClosedAt != nil {
+		t.Fatalf("PR не открыт: %+v", reopened)
+	}
+	if slices.Contains(reopened.AssignedReviewers, first) || !slices.Contains(reopened.AssignedReviewers, second) {
+		t.Fatalf("Неактивный ревьювер не заменён: %v", reopened.AssignedReviewers)
+	}
+	if _, err := svc.ReopenPullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Повторное открытие должно быть идемпотентным: %v", err)
+	}
+
+	// Замены нет: ревьювер остаётся назначенным, а PR всё равно открывается
+	if _, err := svc.ClosePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Ошибка закрытия: %v", err)
+	}
+	if _, err := svc.SetUserActive(ctx, second, false); err != nil {
+		t.Fatalf("Ошибка деактивации: %v", err)
+	}
+	reopened, err = svc.ReopenPullRequest(ctx, "pr-1")
+	if err != nil || !slices.Contains(reopened.AssignedReviewers, second) {
+		t.Fatalf("Ревьювер без замены должен остаться: %+v (%v)", reopened, err)
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+	var types []models.PullRequestEventType
+	for _, e := range events {
+		types = append(types, e.EventType)
+	}
+	want := []models.PullRequestEventType{
+		models.Created, models.ReviewerAssigned, models.ReviewerAssigned,
+		models.Closed, models.Reopened, models.ReviewerReplaced, models.Closed, models.Reopened,
+	}
+	if !slices.Equal(types, want) {
+		t.Fatalf("Ожидались события %v, получено %v", want, types)
+	}
+	if *events[5].OldReviewerId != first || *events[5].Reason != ReasonReopened {
+		t.Fatalf("Неверное событие замены: %+v", events[5])
+	}
+
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Ошибка merge: %v", err)
+	}
+	for name, op := range map[string]func(context.Context, string) (*models.PullRequest, error){
+		"close":  svc.ClosePullRequest,
+		"reopen": svc.ReopenPullRequest,
+	} {
+		if _, err := op(ctx, "pr-1"); !errors.Is(err, ErrPRAlreadyMerged) {
+			t.Fatalf("%s: ожидалась ошибка ErrPRAlreadyMerged, получена %v", name, err)
+		}
+		if _, err := op(ctx, "missing"); !errors.Is(err, ErrPRNotFound) {
+			t.Fatalf("%s: ожидалась ошибка ErrPRNotFound, получена %v", name, err)
+		}
+	}
+}
+
+func TestConcurrentCloseAndMerge(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("author", true), member("u1", true), member("u2", true))
+
+	for i := 0; i < 20; i++ {
+		prId := fmt.Sprintf("pr-%d", i)
+		if _, err := svc.CreatePullRequest(ctx, prId, "PR", "author"); err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		var closeErr, mergeErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, closeErr = svc.ClosePullRequest(ctx, prId)
+		}()
+		go func() {
+			defer wg.Done()
+			_, mergeErr = svc.MergePullRequest(ctx, prId)
+		}()
+		wg.Wait()
+
+		// Выигрывает ровно одна операция, вторая видит её результат
+		pr, err := svc.GetPullRequest(ctx, prId)
+		if err != nil {
+			t.Fatalf("Ошибка получения PR: %v", err)
+		}
+		switch {
+		case closeErr == nil && errors.Is(mergeErr, ErrPRClosed):
+			if pr.Status != models.PullRequestStatusCLOSED || pr.MergedAt != nil || pr.ClosedAt == nil {
+				t.Fatalf("%s: ожидался закрытый PR, получен %+v", prId, pr)
+			}
+		case mergeErr == nil && errors.Is(closeErr, ErrPRAlreadyMerged):
+			if pr.Status != models.PullRequestStatusMERGED || pr.MergedAt == nil || pr.ClosedAt != nil {
+				t.Fatalf("%s: ожидался объединённый PR, получен %+v", prId, pr)
+			}
+		default:
+			t.Fatalf("%s: close и merge не должны оба пройти: close=%v, merge=%v", prId, closeErr, mergeErr)
+		}
+
+		events, err := svc.GetPullRequestHistory(ctx, prId)
+		if err != nil {
+			t.Fatalf("Ошибка получения журнала: %v", err)
+		}
+		last := events[len(events)-1].EventType
+		if (last == models.Merged) != (pr.Status == models.PullRequestStatusMERGED) ||
+			slices.ContainsFunc(events[:len(events)-1], func(e models.PullRequestEvent) bool {
+				return e.EventType == models.Merged || e.EventType == models.Closed
+			}) {
+			t.Fatalf("%s: журнал не совпадает со статусом %s: %+v", prId, pr.Status, events)
+		}
+	}
+}
+
+func TestDraftPullRequest(t *testing.T) {
+	ctx := context.Background()
+	rec := &countingRecorder{}
+	svc := NewService(db.NewMemoryStorage(), Options{Recorder: rec})
+	team := &models.Team{TeamName: "backend", Members: []models.TeamMember{
+		member("author", true), member("u1", true), member("u2", true),
+	}}
+	if err := svc.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	draft, err := svc.CreateDraftPullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания черновика: %v", err)
+	}
+	if !draft.Draft || len(draft.AssignedReviewers) != 0 {
+		t.Fatalf("Черновик не должен получать ревьюверов: %+v", draft)
+	}
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); !errors.Is(err, ErrPRDraft) {
+		t.Fatalf("Ожидалась ошибка ErrPRDraft при merge, получена %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u1"); !errors.Is(err, ErrPRDraft) {
+		t.Fatalf("Ожидалась ошибка ErrPRDraft при переназначении, получена %v", err)
+	}
+
+	// Пока PR был черновиком, u1 деактивирован: ревьюверы выбираются в момент markReady
+	if _, err := svc.SetUserActive(ctx, "u1", false); err != nil {
+		t.Fatalf("Ошибка деактивации: %v", err)
+	}
+	ready, err := svc.MarkPullRequestReady(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка markReady: %v", err)
+	}
+	if ready.Draft || !slices.Equal(ready.AssignedReviewers, []string{"u2"}) {
+		t.Fatalf("Ожидался готовый PR с ревьювером u2, получено %+v", ready)
+	}
+	// Повторный вызов ничего не меняет
+	again, err := svc.MarkPullRequestReady(ctx, "pr-1")
+	if err != nil || !slices.Equal(again.AssignedReviewers, ready.AssignedReviewers) {
+		t.Fatalf("Повторный markReady изменил PR: %+v (%v)", again, err)
+	}
+	if rec.created != 1 || rec.ready != 1 || rec.understaffed != 1 {
+		t.Fatalf("Неверные счётчики: %+v", rec)
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+	var types []models.PullRequestEventType
+	for _, e := range events {
+		types = append(types, e.EventType)
+	}
+	want := []models.PullRequestEventType{models.Created, models.ReadyForReview, models.ReviewerAssigned}
+	if !slices.Equal(types, want) {
+		t.Fatalf("Ожидались события %v, получено %v", want, types)
+	}
+
+	// Закрытый черновик сначала нужно открыть заново
+	if _, err := svc.CreateDraftPullRequest(ctx, "pr-2", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания черновика: %v", err)
+	}
+	if _, err := svc.ClosePullRequest(ctx, "pr-2"); err != nil {
+		t.Fatalf("Ошибка закрытия: %v", err)
+	}
+	if _, err := svc.MarkPullRequestReady(ctx, "pr-2"); !errors.Is(err, ErrPRClosed) {
+		t.Fatalf("Ожидалась ошибка ErrPRClosed, получена %v", err)
+	}
+	if _, err := svc.MarkPullRequestReady(ctx, "missing"); !errors.Is(err, ErrPRNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrPRNotFound, получена %v", err)
+	}
+}
+
+func TestReviewPullRequest(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true))
+
+	approvals := 2
+	_, err := svc.SetTeamSettings(ctx, &models.TeamSettings{
+		TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+	// Без approvals_required значение не сбрасывается
+	settings, err := svc.SetTeamSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerStrategy: models.FirstN})
+	if err != nil || *settings.ApprovalsRequired != 2 {
+		t.Fatalf("Ожидалось сохранённое approvals_required=2, получено %+v (%v)", settings, err)
+	}
+
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	first, second := pr.AssignedReviewers[0], pr.AssignedReviewers[1]
+
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", "u3", models.APPROVED); !errors.Is(err, ErrReviewerNotAssigned) {
+		t.Fatalf("Ожидалась ошибка ErrReviewerNotAssigned, получена %v", err)
+	}
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", first, "LGTM"); !errors.Is(err, ErrUnknownVerdict) {
+		t.Fatalf("Ожидалась ошибка ErrUnknownVerdict, получена %v", err)
+	}
+
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", first, models.APPROVED); err != nil {
+		t.Fatalf("Ошибка ревью: %v", err)
+	}
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", second, models.CHANGESREQUESTED); err != nil {
+		t.Fatalf("Ошибка ревью: %v", err)
+	}
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); !errors.Is(err, ErrNotEnoughApprovals) {
+		t.Fatalf("Ожидалась ошибка ErrNotEnoughApprovals, получена %v", err)
+	}
+
+	// Замена ревьювера сбрасывает его вердикт
+	updated, _, err := svc.ReassignReviewer(ctx, "pr-1", second)
+	if err != nil {
+		t.Fatalf("Ошибка переназначения: %v", err)
+	}
+	if len(updated.Reviews) != 1 || updated.Reviews[0].ReviewerId != first {
+		t.Fatalf("Ожидался только вердикт %s, получено %+v", first, updated.Reviews)
+	}
+
+	reviewed, err := svc.ReviewPullRequest(ctx, "pr-1", "u3", models.APPROVED)
+	if err != nil {
+		t.Fatalf("Ошибка ревью: %v", err)
+	}
+	if len(reviewed.Reviews) != 2 {
+		t.Fatalf("Ожидалось 2 вердикта, получено %+v", reviewed.Reviews)
+	}
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Ошибка merge: %v", err)
+	}
+
+	// Merge идемпотентен, даже если одобрений стало меньше требуемого
+	if _, err := svc.SetTeamReviewersRequired(ctx, "backend", 3); err != nil {
+		t.Fatalf("Ошибка изменения числа ревьюверов: %v", err)
+	}
+	approvals = 3
+	if _, err := svc.SetTeamSettings(ctx, &models.TeamSettings{
+		TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals,
+	}); err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Повторный merge должен быть идемпотентным: %v", err)
+	}
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", first, models.COMMENTED); !errors.Is(err, ErrPRAlreadyMerged) {
+		t.Fatalf("Ожидалась ошибка ErrPRAlreadyMerged, получена %v", err)
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+	var verdicts []models.ReviewVerdict
+	for _, e := range events {
+		if e.EventType == models.Reviewed {
+			verdicts = append(verdicts, *e.Verdict)
+		}
+	}
+	if want := []models.ReviewVerdict{models.APPROVED, models.CHANGESREQUESTED, models.APPROVED}; !slices.Equal(verdicts, want) {
+		t.Fatalf("Ожидались вердикты %v, получено %v", want, verdicts)
+	}
+
+	approvals = MaxReviewerCount + 1
+	if _, err := svc.SetTeamSettings(ctx, &models.TeamSettings{
+		TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals,
+	}); !errors.Is(err, ErrInvalidApprovals) {
+		t.Fatalf("Ожидалась ошибка ErrInvalidApprovals, получена %v", err)
+	}
+}
+
+func TestApprovalsBoundedByReviewers(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true))
+
+	if _, err := svc.SetTeamReviewersRequired(ctx, "backend", 1); err != nil {
+		t.Fatalf("Ошибка изменения числа ревьюверов: %v", err)
+	}
+	approvals := 2
+	settings := &models.TeamSettings{TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals}
+	if _, err := svc.SetTeamSettings(ctx, settings); !errors.Is(err, ErrApprovalsExceed) {
+		t.Fatalf("Ожидалась ошибка ErrApprovalsExceed, получена %v", err)
+	}
+
+	if _, err := svc.SetTeamReviewersRequired(ctx, "backend", 2); err != nil {
+		t.Fatalf("Ошибка изменения числа ревьюверов: %v", err)
+	}
+	if _, err := svc.SetTeamSettings(ctx, settings); err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+	// Понизить число ревьюверов ниже требуемых одобрений нельзя
+	if _, err := svc.SetTeamReviewersRequired(ctx, "backend", 1); !errors.Is(err, ErrApprovalsExceed) {
+		t.Fatalf("Ожидалась ошибка ErrApprovalsExceed, получена %v", err)
+	}
+}
+
+func TestApprovalsWithFewerAssignedReviewers(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("author", true), member("u1", true))
+
+	approvals := 2
+	if _, err := svc.SetTeamSettings(ctx, &models.TeamSettings{
+		TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals,
+	}); err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+	// В команде один кандидат, поэтому PR получает одного ревьювера из двух
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil || len(pr.AssignedReviewers) != 1 {
+		t.Fatalf("Ожидался один ревьювер, получено %+v (%v)", pr, err)
+	}
+
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); !errors.Is(err, ErrNotEnoughApprovals) {
+		t.Fatalf("Ожидалась ошибка ErrNotEnoughApprovals, получена %v", err)
+	}
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", "u1", models.APPROVED); err != nil {
+		t.Fatalf("Ошибка ревью: %v", err)
+	}
+	merged, err := svc.MergePullRequest(ctx, "pr-1")
+	if err != nil || merged.Status != models.PullRequestStatusMERGED {
+		t.Fatalf("Одобрения единственного ревьювера должно хватить для merge, получено %+v (%v)", merged, err)
+	}
+}
+
+func TestRecordExternalMerge(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("author", true), member("u1", true), member("u2", true))
+
+	approvals := 1
+	_, err := svc.SetTeamSettings(ctx, &models.TeamSettings{
+		TeamName: "backend", ReviewerStrategy: models.FirstN, ApprovalsRequired: &approvals,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка изменения настроек: %v", err)
+	}
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if _, err := svc.CreateDraftPullRequest(ctx, "pr-2", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); !errors.Is(err, ErrNotEnoughApprovals) {
+		t.Fatalf("Ожидалась ошибка ErrNotEnoughApprovals, получена %v", err)
+	}
+	// Внешний merge уже случился, поэтому фиксируется без проверок
+	for _, prId := range []string{"pr-1", "pr-2"} {
+		pr, err := svc.RecordExternalMerge(ctx, prId)
+		if err != nil {
+			t.Fatalf("%s: ошибка фиксации merge: %v", prId, err)
+		}
+		if pr.Status != models.PullRequestStatusMERGED || pr.MergedAt == nil || pr.Draft {
+			t.Fatalf("%s: ожидался объединённый PR, получен %+v", prId, pr)
+		}
+	}
+}
+
+// countingRecorder запоминает доменные события сервиса
+type countingRecorder struct {
+	created, ready, understaffed, merged, reassigned, noCandidate int
+}
+
+func (r *countingRecorder) PullRequestCreated(assigned, required int) {
+	r.created++
+	if assigned < required {
+		r.understaffed++
+	}
+}
+func (r *countingRecorder) PullRequestReady(assigned, required int) {
+	r.ready++
+	if assigned < required {
+		r.understaffed++
+	}
+}
+func (r *countingRecorder) PullRequestMerged()  { r.merged++ }
+func (r *countingRecorder) ReviewerReassigned() { r.reassigned++ }
+func (r *countingRecorder) NoCandidate()        { r.noCandidate++ }
+
+func TestRecorderEvents(t *testing.T) {
+	ctx := context.Background()
+	rec := &countingRecorder{}
+	svc := NewService(db.NewMemoryStorage(), Options{Recorder: rec})
+
+	team := &models.Team{TeamName: "backend", Members: []models.TeamMember{member("author", true), member("u1", true)}}
+	if err := svc.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	// В команде только один кандидат при двух требуемых
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u1"); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("Ожидалась ошибка ErrNoCandidate, получена %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+			t.Fatalf("Ошибка merge: %v", err)
+		}
+	}
+
+	want := countingRecorder{created: 1, understaffed: 1, merged: 1, noCandidate: 1}
+	if *rec != want {
+		t.Fatalf("Ожидались события %+v, получены %+v", want, *rec)
+	}
+}
+
+func TestDeactivateTeamUsers(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", false))
+
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	if _, err := svc.SetUserActive(ctx, "u3", true); err != nil {
+		t.Fatalf("Ошибка активации: %v", err)
+	}
+
+	report, err := svc.DeactivateTeamUsers(ctx, "backend", []string{"u1", "u2"})
+	if err != nil {
+		t.Fatalf("Ошибка деактивации: %v", err)
+	}
+
+	if len(report.Reassigned) != 1 || report.Reassigned[0].NewUserId != "u3" {
+		t.Fatalf("Ожидалась одна замена на u3, получено %v", report.Reassigned)
+	}
+	if len(report.NotReassigned) != 1 || report.NotReassigned[0].Reason != string(models.NOCANDIDATE) {
+		t.Fatalf("Ожидался один PR без замены, получено %v", report.NotReassigned)
+	}
+
+	for _, id := range []string{"u1", "u2"} {
+		user, err := storage.GetUser(ctx, id)
+		if err != nil {
+			t.Fatalf("Ошибка получения пользователя: %v", err)
+		}
+		if user.IsActive {
+			t.Fatalf("Пользователь %s должен быть неактивен", id)
+		}
+	}
+}
+
+func TestDeactivateTeamUsersRollsBack(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t, "backend", member("u1", true), member("u2", true))
+
+	// Второй пользователь не существует, поэтому деактивация первого откатывается
+	if _, err := svc.DeactivateTeamUsers(ctx, "backend", []string{"u1", "ghost"}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrUserNotFound, получена %v", err)
+	}
+
+	user, err := storage.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if !user.IsActive {
+		t.Fatal("Деактивация должна быть откачена")
+	}
+}
+
+func TestPullRequestHistory(t *testing.T) {
+	ctx := WithActor(context.Background(), "alice")
+	svc, _ := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true))
+
+	pr, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author")
+	if err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	oldReviewer := pr.AssignedReviewers[0]
+
+	_, newReviewer, err := svc.ReassignReviewer(context.Background(), "pr-1", oldReviewer)
+	if err != nil {
+		t.Fatalf("Ошибка переназначения: %v", err)
+	}
+	// Повторный merge не должен дублировать событие
+	for range 2 {
+		if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+			t.Fatalf("Ошибка merge: %v", err)
+		}
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+
+	var types []models.PullRequestEventType
+	for _, e := range events {
+		types = append(types, e.EventType)
+	}
+	want := []models.PullRequestEventType{
+		models.Created, models.ReviewerAssigned, models.ReviewerAssigned, models.ReviewerReplaced, models.Merged,
+	}
+	if !slices.Equal(types, want) {
+		t.Fatalf("Ожидались события %v, получено %v", want, types)
+	}
+
+	if events[0].Actor != "alice" || *events[1].Reason != ReasonAuto {
+		t.Fatalf("Неверные инициатор или причина создания: %+v", events[:2])
+	}
+	replaced := events[3]
+	if replaced.Actor != DefaultActor || *replaced.OldReviewerId != oldReviewer ||
+		*replaced.NewReviewerId != newReviewer || *replaced.Reason != ReasonManual {
+		t.Fatalf("Неверное событие замены: %+v", replaced)
+	}
+
+	if _, err := svc.GetPullRequestHistory(ctx, "missing"); !errors.Is(err, ErrPRNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrPRNotFound, получена %v", err)
+	}
+}
+
+func TestGetUserPullRequestsPages(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t, "backend", member("author", true), member("u1", true))
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := base.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	// pr-0 без created_at считается самым старым; у pr-2 и pr-3 одинаковая дата
+	prs := []models.PullRequest{
+		{PullRequestId: "pr-0", Status: models.PullRequestStatusOPEN},
+		{PullRequestId: "pr-1", Status: models.PullRequestStatusOPEN, CreatedAt: at(1)},
+		{PullRequestId: "pr-3", Status: models.PullRequestStatusOPEN, CreatedAt: at(2)},
+		{PullRequestId: "pr-2", Status: models.PullRequestStatusMERGED, CreatedAt: at(2)},
+		{PullRequestId: "pr-4", Status: models.PullRequestStatusOPEN, CreatedAt: at(3)},
+	}
+	for _, pr := range prs {
+		pr.AuthorId, pr.AssignedReviewers = "author", []string{"u1"}
+		if _, err := storage.CreatePullRequest(ctx, &pr); err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+	}
+
+	// collect обходит все страницы и возвращает идентификаторы PR по порядку
+	collect := func(query PullRequestQuery) []string {
+		t.Helper()
+		var ids []string
+		for pages := 0; ; pages++ {
+			if pages > len(prs) {
+				t.Fatal("Обход страниц не завершился")
+			}
+			page, next, err := svc.GetUserPullRequests(ctx, "u1", query)
+			if err != nil {
+				t.Fatalf("Ошибка получения PR: %v", err)
+			}
+			for _, pr := range page {
+				ids = append(ids, pr.PullRequestId)
+			}
+			if next == "" {
+				return ids
+			}
+			query.Cursor = next
+		}
+	}
+
+	open := models.PullRequestStatusOPEN
+	tests := []struct {
+		name  string
+		query PullRequestQuery
+		want  []string
+	}{
+		{"по умолчанию", PullRequestQuery{}, []string{"pr-0", "pr-1", "pr-2", "pr-3", "pr-4"}},
+		{"по страницам", PullRequestQuery{Limit: 2}, []string{"pr-0", "pr-1", "pr-2", "pr-3", "pr-4"}},
+		{"от новых к старым", PullRequestQuery{Limit: 2, Descending: true}, []string{"pr-4", "pr-3", "pr-2", "pr-1", "pr-0"}},
+		{"по статусу", PullRequestQuery{Limit: 1, Status: &open}, []string{"pr-0", "pr-1", "pr-3", "pr-4"}},
+		{"по датам", PullRequestQuery{CreatedAfter: at(1), CreatedBefore: at(3)}, []string{"pr-1", "pr-2", "pr-3"}},
+		{"до даты", PullRequestQuery{CreatedBefore: at(1)}, []string{"pr-0"}},
+	}
+	for _, tt := range tests {
+		if got := collect(tt.query); !slices.Equal(got, tt.want) {
+			t.Fatalf("%s: ожидались PR %v, получены %v", tt.name, tt.want, got)
+		}
+	}
+
+	page, _, err := svc.GetUserPullRequests(ctx, "u1", PullRequestQuery{Limit: 1, Descending: true})
+	if err != nil || page[0].CreatedAt == nil || !page[0].CreatedAt.Equal(*at(3)) {
+		t.Fatalf("Ожидался createdAt последнего PR, получено %+v (%v)", page, err)
+	}
+
+	for _, query := range []PullRequestQuery{{Limit: -1}, {Limit: MaxPageLimit + 1}} {
+		if _, _, err := svc.GetUserPullRequests(ctx, "u1", query); !errors.Is(err, ErrInvalidLimit) {
+			t.Fatalf("limit %d: ожидалась ошибка ErrInvalidLimit, получена %v", query.Limit, err)
+		}
+	}
+	if _, _, err := svc.GetUserPullRequests(ctx, "u1", PullRequestQuery{Cursor: "не курсор"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("Ожидалась ошибка ErrInvalidCursor, получена %v", err)
+	}
+	status := models.PullRequestStatus("DRAFT")
+	if _, _, err := svc.GetUserPullRequests(ctx, "u1", PullRequestQuery{Status: &status}); !errors.Is(err, ErrUnknownStatus) {
+		t.Fatalf("Ожидалась ошибка ErrUnknownStatus, получена %v", err)
+	}
+}
+
+func TestPullRequestQueryUsesUTC(t *testing.T) {
+	// PostgreSQL сравнивает TIMESTAMP по часам на циферблате, поэтому смещение нужно снять до запроса
+	msk := time.FixedZone("MSK", 3*60*60)
+	after := time.Date(2026, 1, 1, 10, 0, 0, 0, msk)
+	before := after.Add(time.Hour)
+
+	filter, err := PullRequestQuery{CreatedAfter: &after, CreatedBefore: &before}.filter()
+	if err != nil {
+		t.Fatalf("Ошибка построения фильтра: %v", err)
+	}
+	want := time.Date(2026, 1, 1, 7, 0, 0, 0, time.UTC)
+	if *filter.CreatedAfter != want || *filter.CreatedBefore != want.Add(time.Hour) {
+		t.Fatalf("Ожидалось окно с %v в UTC, получено %v - %v", want, filter.CreatedAfter, filter.CreatedBefore)
+	}
+}
+
+func TestListPullRequests(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("author", true), member("u1", true))
+	if err := svc.CreateTeam(ctx, &models.Team{TeamName: "frontend", Members: []models.TeamMember{
+		member("designer", true), member("u2", true),
+	}}); err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+
+	for _, pr := range []struct{ id, name, author string }{
+		{"pr-1", "Add search", "author"},
+		{"pr-2", "Fix SEARCH index", "author"},
+		{"pr-3", "New button", "designer"},
+	} {
+		if _, err := svc.CreatePullRequest(ctx, pr.id, pr.name, pr.author); err != nil {
+			t.Fatalf("Ошибка создания PR: %v", err)
+		}
+	}
+	if _, err := svc.ReviewPullRequest(ctx, "pr-1", "u1", models.APPROVED); err != nil {
+		t.Fatalf("Ошибка ревью: %v", err)
+	}
+
+	ptr := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		query PullRequestQuery
+		want  []string
+	}{
+		{"без фильтров", PullRequestQuery{}, []string{"pr-1", "pr-2", "pr-3"}},
+		{"по автору", PullRequestQuery{AuthorId: ptr("designer")}, []string{"pr-3"}},
+		{"по команде", PullRequestQuery{TeamName: ptr("backend")}, []string{"pr-1", "pr-2"}},
+		{"по ревьюверу", PullRequestQuery{ReviewerId: ptr("u2")}, []string{"pr-3"}},
+		{"по названию", PullRequestQuery{NameContains: ptr("search")}, []string{"pr-1", "pr-2"}},
+		{"всё вместе", PullRequestQuery{TeamName: ptr("backend"), NameContains: ptr("fix")}, []string{"pr-2"}},
+		{"нет совпадений", PullRequestQuery{TeamName: ptr("missing")}, []string{}},
+	}
+	for _, tt := range tests {
+		prs, next, err := svc.ListPullRequests(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("%s: ошибка получения PR: %v", tt.name, err)
+		}
+		got := []string{}
+		for _, pr := range prs {
+			got = append(got, pr.PullRequestId)
+		}
+		if !slices.Equal(got, tt.want) || next != "" {
+			t.Fatalf("%s: ожидались PR %v, получены %v (курсор %q)", tt.name, tt.want, got, next)
+		}
+	}
+
+	// Список возвращает PR целиком, вместе с вердиктами
+	prs, _, err := svc.ListPullRequests(ctx, PullRequestQuery{AuthorId: ptr("author"), Limit: 1})
+	if err != nil || len(prs) != 1 {
+		t.Fatalf("Ожидался один PR, получено %+v (%v)", prs, err)
+	}
+	if len(prs[0].Reviews) != 1 || prs[0].Reviews[0].Verdict != models.APPROVED || prs[0].CreatedAt == nil {
+		t.Fatalf("Неверный PR в списке: %+v", prs[0])
+	}
+}
+
+func TestPullRequestHistoryRollsBack(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("author", true), member("u1", true))
+
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	// Замены нет, поэтому событие reviewer_replaced не должно сохраниться
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u1"); !errors.Is(err, ErrNoCandidate) {
+		t.Fatalf("Ожидалась ошибка ErrNoCandidate, получена %v", err)
+	}
+
+	events, err := svc.GetPullRequestHistory(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения журнала: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Ожидалось 2 события, получено %+v", events)
+	}
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t, "backend", member("author", true), member("u1", true), member("u2", true))
+
+	invalid := []struct {
+		url, secret string
+		events      []models.WebhookEventType
+	}{
+		{"ftp://bot.example.com", "s", nil},
+		{"/hooks", "s", nil},
+		{"https://bot.example.com", "", nil},
+		{"https://bot.example.com", "s", []models.WebhookEventType{"pull_request.deleted"}},
+	}
+	for _, tc := range invalid {
+		if _, err := svc.CreateWebhookSubscription(ctx, tc.url, tc.secret, tc.events); !errors.Is(err, ErrInvalidWebhook) {
+			t.Fatalf("Для %+v ожидалась ошибка ErrInvalidWebhook, получена %v", tc, err)
+		}
+	}
+
+	sub, err := svc.CreateWebhookSubscription(ctx, "https://bot.example.com", "s", []models.WebhookEventType{models.UserDeactivated})
+	if err != nil {
+		t.Fatalf("Ошибка создания подписки: %v", err)
+	}
+
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	// Откаченная деактивация не должна оставлять событий в outbox
+	if _, err := svc.DeactivateTeamUsers(ctx, "backend", []string{"u1", "ghost"}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrUserNotFound, получена %v", err)
+	}
+	if _, err := svc.SetUserActive(ctx, "u1", false); err != nil {
+		t.Fatalf("Ошибка деактивации: %v", err)
+	}
+	// Повторная деактивация ничего не меняет и не отправляется
+	if _, err := svc.SetUserActive(ctx, "u1", false); err != nil {
+		t.Fatalf("Ошибка деактивации: %v", err)
+	}
+
+	now := time.Now()
+	deliveries, err := storage.ClaimWebhookDeliveries(ctx, now, now, 10)
+	if err != nil {
+		t.Fatalf("Ошибка выборки вебхуков: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Event != models.UserDeactivated {
+		t.Fatalf("Ожидалось одно событие user.deactivated, получено %+v", deliveries)
+	}
+
+	if err := svc.DeleteWebhookSubscription(ctx, sub.SubscriptionId); err != nil {
+		t.Fatalf("Ошибка удаления подписки: %v", err)
+	}
+	if err := svc.DeleteWebhookSubscription(ctx, sub.SubscriptionId); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrSubscriptionNotFound, получена %v", err)
+	}
+}
+
+// unavailableStorage имитирует недоступную БД для чтения команд и пользователей
+type unavailableStorage struct {
+	db.Repository
+}
+
+func (u unavailableStorage) InTx(_ context.Context, fn func(tx db.Repository) error) error {
+	return fn(u)
+}
+
+func (unavailableStorage) GetTeam(context.Context, string) (*models.Team, error) {
+	return nil, fmt.Errorf("ошибка при получении команды: %w", db.ErrUnavailable)
+}
+
+func (unavailableStorage) GetUser(context.Context, string) (*models.User, error) {
+	return nil, fmt.Errorf("ошибка при получении пользователя: %w", db.ErrUnavailable)
+}
+
+func TestStorageErrorsMapping(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(t, "backend", member("u1", true))
+
+	if _, err := svc.GetTeam(ctx, "frontend"); !errors.Is(err, ErrTeamNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrTeamNotFound, получена %v", err)
+	}
+	if _, err := svc.SetUserActive(ctx, "ghost", true); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrUserNotFound, получена %v", err)
+	}
+	if _, err := svc.MergePullRequest(ctx, "pr-404"); !errors.Is(err, ErrPRNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrPRNotFound, получена %v", err)
+	}
+
+	// Сбой БД не должен выдаваться за отсутствие записи
+	broken := NewService(unavailableStorage{Repository: db.NewMemoryStorage()}, Options{})
+	if _, err := broken.GetTeam(ctx, "backend"); !errors.Is(err, db.ErrUnavailable) || errors.Is(err, ErrTeamNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrUnavailable, получена %v", err)
+	}
+	if _, err := broken.SetUserActive(ctx, "u1", true); !errors.Is(err, db.ErrUnavailable) || errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("Ожидалась ошибка ErrUnavailable, получена %v", err)
+	}
+}
+
+func TestCreatePullRequestRejectsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t, "backend",
+		member("author", true), member("u1", true), member("u2", true), member("u3", true))
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := svc.CreatePullRequest(ctx, "pr-1", fmt.Sprintf("Attempt %d", i), "author")
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrPRExists):
+			t.Fatalf("Ожидалась ошибка ErrPRExists, получена %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("Ожидалось ровно одно создание PR, получено %d", created)
+	}
+
+	// Повторное создание не перезаписывает существующий PR
+	pr, err := storage.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения PR: %v", err)
+	}
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "Overwrite", "author"); !errors.Is(err, ErrPRExists) {
+		t.Fatalf("Ожидалась ошибка ErrPRExists, получена %v", err)
+	}
+	after, err := storage.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("Ошибка получения PR: %v", err)
+	}
+	if after.PullRequestName != pr.PullRequestName || !slices.Equal(after.AssignedReviewers, pr.AssignedReviewers) {
+		t.Fatalf("PR перезаписан: было %+v, стало %+v", pr, after)
+	}
+}
+
+// statsWindowStorage запоминает окно, с которым запрошена статистика
+type statsWindowStorage struct {
+	db.Repository
+	from, to *time.Time
+}
+
+func (s *statsWindowStorage) GetReviewerStats(_ context.Context, from, to *time.Time, _ *string) ([]models.ReviewerStats, error) {
+	s.from, s.to = from, to
+	return nil, nil
+}
+
+func TestStatsWindowUsesUTC(t *testing.T) {
+	storage := &statsWindowStorage{}
+	svc := NewService(storage, Options{})
+
+	msk := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2026, 1, 1, 10, 0, 0, 0, msk)
+	to := from.Add(24 * time.Hour)
+	if _, err := svc.GetTeamStats(context.Background(), &from, &to); err != nil {
+		t.Fatalf("Ошибка получения статистики: %v", err)
+	}
+
+	want := time.Date(2026, 1, 1, 7, 0, 0, 0, time.UTC)
+	if *storage.from != want || *storage.to != want.Add(24*time.Hour) {
+		t.Fatalf("Ожидалось окно с %v в UTC, получено %v - %v", want, storage.from, storage.to)
+	}
+}
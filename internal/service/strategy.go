@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"sort"
+	"sync"
+)
+
+// DefaultReviewerStrategy используется для команд, не задавших стратегию явно
+const DefaultReviewerStrategy = models.LeastLoaded
+
+// ReviewerSelectionStrategy выбирает до count ревьюверов среди кандидатов команды.
+// Кандидаты уже отфильтрованы: активны и не являются автором.
+// storage передаётся вызывающим, чтобы выбор шёл в рамках его транзакции.
+type ReviewerSelectionStrategy interface {
+	Select(ctx context.Context, storage db.Repository, teamName string, candidates []string, count int) ([]string, error)
+}
+
+// FirstNStrategy берёт первых count кандидатов в исходном порядке
+type FirstNStrategy struct{}
+
+func (FirstNStrategy) Select(_ context.Context, _ db.Repository, _ string, candidates []string, count int) ([]string, error) {
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	return candidates, nil
+}
+
+// RandomStrategy выбирает кандидатов равновероятно
+type RandomStrategy struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRandomStrategy создаёт стратегию со своим генератором, инициализированным seed
+func NewRandomStrategy(seed int64) *RandomStrategy {
+	return &RandomStrategy{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (r *RandomStrategy) Select(_ context.Context, _ db.Repository, _ string, candidates []string, count int) ([]string, error) {
+	shuffled := append([]string(nil), candidates...)
+
+	r.mu.Lock()
+	r.rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	r.mu.Unlock()
+
+	if len(shuffled) > count {
+		shuffled = shuffled[:count]
+	}
+	return shuffled, nil
+}
+
+// RoundRobinStrategy назначает кандидатов по кругу.
+// Курсор хранится в настройках команды, поэтому очередь сохраняется между перезапусками.
+type RoundRobinStrategy struct{}
+
+func (RoundRobinStrategy) Select(ctx context.Context, storage db.Repository, teamName string, candidates []string, count int) ([]string, error) {
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	// Порядок из БД не гарантирован, поэтому очередь строим по user_id
+	ordered := append([]string(nil), candidates...)
+	sort.Strings(ordered)
+
+	cursor, err := storage.AdvanceRoundRobinCursor(ctx, teamName, count)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении курсора round-robin: %w", err)
+	}
+
+	start := int(cursor % int64(len(ordered)))
+	reviewers := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		reviewers = append(reviewers, ordered[(start+i)%len(ordered)])
+	}
+	return reviewers, nil
+}
+
+// LeastLoadedStrategy выбирает кандидатов с наименьшим числом открытых ревью,
+// при равной загрузке выбор случайный
+type LeastLoadedStrategy struct{}
+
+func (LeastLoadedStrategy) Select(ctx context.Context, storage db.Repository, _ string, candidates []string, count int) ([]string, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return nil, nil
+	}
+
+	counts, err := storage.GetOpenReviewCounts(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении загрузки ревьюверов: %w", err)
+	}
+
+	// Перемешиваем до стабильной сортировки, чтобы равные по загрузке выбирались случайно
+	ordered := append([]string(nil), candidates...)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return counts[ordered[i]] < counts[ordered[j]]
+	})
+
+	if len(ordered) > count {
+		ordered = ordered[:count]
+	}
+	return ordered, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidWebhook       = &ServiceError{Code: models.INVALIDREQUEST, Message: "нужны абсолютный http(s) URL, непустой секрет и известные события"}
+	ErrSubscriptionNotFound = &ServiceError{Code: models.NOTFOUND, Message: "подписка не найдена"}
+)
+
+// webhookEvents — события, на которые можно подписаться
+var webhookEvents = []models.WebhookEventType{
+	models.PullRequestCreated,
+	models.PullRequestReadyForReview,
+	models.PullRequestReassigned,
+	models.PullRequestReviewed,
+	models.PullRequestMerged,
+	models.PullRequestClosed,
+	models.PullRequestReopened,
+	models.UserDeactivated,
+}
+
+// CreateWebhookSubscription подписывает rawURL на события; пустой events означает все события
+func (s *Service) CreateWebhookSubscription(ctx context.Context, rawURL, secret string, events []models.WebhookEventType) (*models.WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || secret == "" {
+		return nil, ErrInvalidWebhook
+	}
+	for _, event := range events {
+		if !slices.Contains(webhookEvents, event) {
+			return nil, ErrInvalidWebhook
+		}
+	}
+
+	sub := &models.WebhookSubscription{
+		SubscriptionId: uuid.NewString(),
+		Url:            rawURL,
+		Events:         slices.Compact(slices.Sorted(slices.Values(events))),
+		CreatedAt:      time.Now().UTC(),
+	}
+	if sub.Events == nil {
+		sub.Events = []models.WebhookEventType{}
+	}
+
+	if err := s.storage.CreateWebhookSubscription(ctx, sub, secret); err != nil {
+		return nil, fmt.Errorf("ошибка при сохранении подписки: %w", err)
+	}
+	return sub, nil
+}
+
+// ListWebhookSubscriptions возвращает подписки без секретов
+func (s *Service) ListWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	subs, err := s.storage.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении подписок: %w", err)
+	}
+	return subs, nil
+}
+
+// DeleteWebhookSubscription удаляет подписку; недоставленные ей события отбрасываются
+func (s *Service) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if err := s.storage.DeleteWebhookSubscription(ctx, id); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("ошибка при удалении подписки: %w", err)
+	}
+	return nil
+}
+
+// notify ставит событие в outbox вебхуков. Вызывается внутри транзакции изменения,
+// поэтому событие отправляется, только если изменение зафиксировано.
+func (s *Service) notify(ctx context.Context, payload models.WebhookPayload) error {
+	payload.Actor = Actor(ctx)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации вебхука: %w", err)
+	}
+
+	if err := s.storage.EnqueueWebhook(ctx, payload.Event, body, payload.OccurredAt); err != nil {
+		return fmt.Errorf("ошибка постановки вебхука в очередь: %w", err)
+	}
+	return nil
+}
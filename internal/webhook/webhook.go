@@ -0,0 +1,209 @@
+// Package webhook отправляет подписчикам события из outbox вебхуков: подписывает тело
+// HMAC-SHA256 с секретом подписки и повторяет неудачные доставки с экспоненциальной задержкой.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/logging"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Заголовки запроса к получателю
+const (
+	// SignatureHeader содержит "sha256=" и HMAC-SHA256 тела в hex
+	SignatureHeader = "X-Webhook-Signature-256"
+	EventHeader     = "X-Webhook-Event"
+	// DeliveryHeader — id доставки; при повторах не меняется, получатель может по нему отбрасывать дубли
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// Options задаёт параметры доставки; нулевые значения заменяются значениями по умолчанию
+type Options struct {
+	// PollInterval — как часто проверять outbox
+	PollInterval time.Duration
+	// Timeout ограничивает один запрос к получателю
+	Timeout time.Duration
+	// MaxAttempts — после стольких неудач доставка прекращается
+	MaxAttempts int
+	// InitialBackoff — задержка перед второй попыткой, дальше она удваивается до MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// BatchSize — сколько доставок выбирается за один проход
+	BatchSize int
+	Client    *http.Client
+	Logger    *slog.Logger
+}
+
+// Dispatcher разбирает outbox вебхуков
+type Dispatcher struct {
+	storage db.Repository
+	opts    Options
+}
+
+// NewDispatcher создаёт Dispatcher; запускается вызовом Run
+func NewDispatcher(storage db.Repository, opts Options) *Dispatcher {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 8
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = max(10*time.Minute, opts.InitialBackoff)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{}
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Dispatcher{storage: storage, opts: opts}
+}
+
+// Sign возвращает значение SignatureHeader для body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run доставляет события каждые PollInterval, пока ctx не отменён.
+// Доставки, прерванные остановкой, повторятся после истечения их аренды.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Полная пачка означает, что в очереди могут остаться готовые доставки
+		for {
+			n, err := d.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				d.opts.Logger.LogAttrs(ctx, slog.LevelWarn, "Ошибка разбора очереди вебхуков", logging.Err(err))
+			}
+			if err != nil || n < d.opts.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue отправляет одну пачку доставок, срок которых наступил, и возвращает её размер
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	// Аренда длиннее таймаута запроса, чтобы доставку не взял другой экземпляр, пока эта ещё идёт
+	deliveries, err := d.storage.ClaimWebhookDeliveries(ctx, now, now.Add(2*d.opts.Timeout), d.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка выборки вебхуков: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery db.WebhookDelivery) {
+	sendErr := d.send(ctx, delivery)
+	if ctx.Err() != nil {
+		// Остановка: результат не фиксируем, доставка повторится после аренды
+		return
+	}
+
+	now := time.Now()
+	var err error
+	switch {
+	case sendErr == nil:
+		err = d.storage.CompleteWebhookDelivery(ctx, delivery.Id, now)
+	case delivery.Attempt >= d.opts.MaxAttempts:
+		d.opts.Logger.LogAttrs(ctx, slog.LevelError, "Вебхук не доставлен, попытки исчерпаны",
+			slog.Int64("delivery_id", delivery.Id),
+			slog.String("subscription_id", delivery.SubscriptionId),
+			slog.Int("attempt", delivery.Attempt),
+			logging.Err(sendErr),
+		)
+		err = d.storage.FailWebhookDelivery(ctx, delivery.Id, sendErr.Error(), now)
+	default:
+		next := now.Add(d.backoff(delivery.Attempt))
+		d.opts.Logger.LogAttrs(ctx, slog.LevelWarn, "Вебхук не доставлен, будет повтор",
+			slog.Int64("delivery_id", delivery.Id),
+			slog.String("subscription_id", delivery.SubscriptionId),
+			slog.Int("attempt", delivery.Attempt),
+			slog.Time("next_attempt_at", next),
+			logging.Err(sendErr),
+		)
+		err = d.storage.RetryWebhookDelivery(ctx, delivery.Id, sendErr.Error(), next)
+	}
+	if err != nil {
+		d.opts.Logger.LogAttrs(ctx, slog.LevelError, "Ошибка сохранения результата доставки вебхука",
+			slog.Int64("delivery_id", delivery.Id), logging.Err(err))
+	}
+}
+
+// backoff возвращает задержку после неудачной попытки attempt (начиная с 1)
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.opts.InitialBackoff
+	for i := 1; i < attempt && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.MaxBackoff)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery db.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("некорректный запрос: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reviewer-webhook")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	// Дочитываем ответ, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("получатель ответил %d", resp.StatusCode)
+	}
+	return nil
+}
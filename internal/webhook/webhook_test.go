@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/models"
+	"pr-reviewer/internal/service"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver — получатель вебхуков, отвечающий кодами из statuses по очереди (затем 200)
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	if len(rc.statuses) > 0 {
+		w.WriteHeader(rc.statuses[0])
+		rc.statuses = rc.statuses[1:]
+	}
+}
+
+func newTestService(t *testing.T) (*service.Service, *db.MemoryStorage) {
+	t.Helper()
+
+	storage := db.NewMemoryStorage()
+	svc := service.NewService(storage, service.Options{})
+	err := svc.CreateTeam(context.Background(), &models.Team{TeamName: "backend", Members: []models.TeamMember{
+		{UserId: "author", Username: "author", IsActive: true},
+		{UserId: "u1", Username: "u1", IsActive: true},
+		{UserId: "u2", Username: "u2", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("Ошибка создания команды: %v", err)
+	}
+	return svc, storage
+}
+
+func TestDeliverSignedPayload(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t)
+
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	if _, err := svc.CreateWebhookSubscription(ctx, srv.URL, "s3cr3t", []models.WebhookEventType{models.PullRequestCreated}); err != nil {
+		t.Fatalf("Ошибка создания подписки: %v", err)
+	}
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+	// merge не входит в фильтр подписки
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("Ошибка merge: %v", err)
+	}
+
+	d := NewDispatcher(storage, Options{})
+	if n, err := d.DeliverDue(ctx); err != nil || n != 1 {
+		t.Fatalf("Ожидалась одна доставка, получено %d (%v)", n, err)
+	}
+	if n, _ := d.DeliverDue(ctx); n != 0 {
+		t.Fatalf("Доставленное событие не должно повторяться, получено %d", n)
+	}
+
+	if len(rc.requests) != 1 {
+		t.Fatalf("Ожидался один запрос, получено %d", len(rc.requests))
+	}
+	req, body := rc.requests[0], rc.bodies[0]
+	if got := req.Header.Get(SignatureHeader); got != Sign("s3cr3t", body) {
+		t.Fatalf("Неверная подпись %q", got)
+	}
+	if got := req.Header.Get(EventHeader); got != string(models.PullRequestCreated) {
+		t.Fatalf("Неверное событие %q", got)
+	}
+
+	var payload models.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Ошибка декодирования тела: %v", err)
+	}
+	if payload.PullRequest == nil || payload.PullRequest.PullRequestId != "pr-1" || payload.Actor != service.DefaultActor {
+		t.Fatalf("Неверное тело вебхука: %s", body)
+	}
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t)
+
+	rc := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	if _, err := svc.CreateWebhookSubscription(ctx, srv.URL, "s3cr3t", nil); err != nil {
+		t.Fatalf("Ошибка создания подписки: %v", err)
+	}
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	d := NewDispatcher(storage, Options{InitialBackoff: 20 * time.Millisecond, MaxBackoff: time.Second})
+	for attempt := 1; attempt <= 3; attempt++ {
+		if n, err := d.DeliverDue(ctx); err != nil || n != 1 {
+			t.Fatalf("Попытка %d: ожидалась одна доставка, получено %d (%v)", attempt, n, err)
+		}
+		// До истечения задержки доставка не повторяется
+		if n, _ := d.DeliverDue(ctx); n != 0 {
+			t.Fatalf("Попытка %d: повтор раньше задержки", attempt)
+		}
+		time.Sleep(d.backoff(attempt) + 10*time.Millisecond)
+	}
+
+	if len(rc.requests) != 3 {
+		t.Fatalf("Ожидалось 3 запроса, получено %d", len(rc.requests))
+	}
+	if rc.requests[0].Header.Get(DeliveryHeader) != rc.requests[2].Header.Get(DeliveryHeader) {
+		t.Fatal("Повторы должны сохранять id доставки")
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	svc, storage := newTestService(t)
+
+	rc := &receiver{statuses: []int{500, 500, 500}}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	if _, err := svc.CreateWebhookSubscription(ctx, srv.URL, "s3cr3t", nil); err != nil {
+		t.Fatalf("Ошибка создания подписки: %v", err)
+	}
+	if _, err := svc.CreatePullRequest(ctx, "pr-1", "PR", "author"); err != nil {
+		t.Fatalf("Ошибка создания PR: %v", err)
+	}
+
+	d := NewDispatcher(storage, Options{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	for range 4 {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			t.Fatalf("Ошибка доставки: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if len(rc.requests) != 2 {
+		t.Fatalf("Ожидалось 2 попытки, получено %d", len(rc.requests))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(db.NewMemoryStorage(), Options{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := d.backoff(i + 1); got != expected {
+			t.Fatalf("Попытка %d: ожидалась задержка %v, получено %v", i+1, expected, got)
+		}
+	}
+}
@@ -1,35 +1,175 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"pr-reviewer/internal/api"
+	"pr-reviewer/internal/config"
 	"pr-reviewer/internal/db"
+	"pr-reviewer/internal/integrations/gitlab"
+	"pr-reviewer/internal/logging"
+	"pr-reviewer/internal/metrics"
 	"pr-reviewer/internal/service"
+	"pr-reviewer/internal/webhook"
+	"syscall"
 )
 
 func main() {
-	dbConnStr := os.Getenv("DATABASE_URL")
-	if dbConnStr == "" {
-		log.Fatal("пустой DATABASE_URL")
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatalf("ошибка конфигурации: %v", err)
+	}
+
+	if err := run(cfg); err != nil {
+		slog.Error("Сервис остановлен с ошибкой", logging.Err(err))
+		os.Exit(1)
 	}
+}
 
-	storage, err := db.NewStorage(dbConnStr)
+// run запускает HTTP-сервер и блокируется до SIGINT/SIGTERM,
+// после чего дожидается завершения обрабатываемых запросов и закрывает хранилище
+func run(cfg config.Config) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		return err
+	}
+	logger, err := logging.New(os.Stdout, level, cfg.Log.Format)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	// Сообщения стандартного пакета log тоже пойдут через этот логгер
+	slog.SetDefault(logger)
+
+	logger.Info("Эффективная конфигурация", slog.String("config", cfg.String()))
 
-	svc := service.NewService(storage)
-	server := api.NewServer(svc)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	handler := api.Handler(server)
+	var m *metrics.Metrics
+	if cfg.Features.Metrics {
+		m = metrics.New()
+	}
+
+	var storage db.Repository
+	switch cfg.Database.Storage {
+	case "postgres":
+		pgStorage, err := db.NewStorage(ctx, cfg.Database.URL, db.PoolOptions{
+			MaxOpenConns:    cfg.Database.MaxOpenConns,
+			MaxIdleConns:    cfg.Database.MaxIdleConns,
+			ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+			ConnMaxIdleTime: cfg.Database.ConnMaxIdleTime,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := pgStorage.Close(); err != nil {
+				logger.Error("Ошибка закрытия соединений с БД", logging.Err(err))
+			}
+		}()
+		storage = pgStorage
+		if m != nil {
+			m.RegisterDBStats(pgStorage.Stats)
+		}
+	case "memory":
+		logger.Warn("Используется хранилище в памяти, данные будут потеряны при остановке")
+		storage = db.NewMemoryStorage()
+	default:
+		return fmt.Errorf("неизвестное хранилище %q", cfg.Database.Storage)
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	serviceOpts := service.Options{ReviewerCount: cfg.Reviewers.DefaultCount}
+	if m != nil {
+		storage = m.InstrumentRepository(storage)
+		serviceOpts.Recorder = m
 	}
 
-	log.Printf("Server listening on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, handler))
+	svc := service.NewService(storage, serviceOpts)
+
+	dispatcher := webhook.NewDispatcher(storage, webhook.Options{
+		PollInterval:   cfg.Webhooks.PollInterval,
+		Timeout:        cfg.Webhooks.Timeout,
+		MaxAttempts:    cfg.Webhooks.MaxAttempts,
+		InitialBackoff: cfg.Webhooks.InitialBackoff,
+		MaxBackoff:     cfg.Webhooks.MaxBackoff,
+		Logger:         logger,
+	})
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatcher.Run(dispatcherCtx)
+	}()
+	// Останавливаем доставку до закрытия хранилища; прерванные доставки повторятся после перезапуска
+	defer func() {
+		stopDispatcher()
+		<-dispatcherDone
+	}()
+
+	var gitlabReviewers gitlab.ReviewerClient
+	if cfg.Integrations.GitLab.APIURL != "" {
+		gitlabReviewers = gitlab.NewAPIClient(cfg.Integrations.GitLab.APIURL, cfg.Integrations.GitLab.APIToken, nil)
+	}
+
+	server := api.NewServer(svc, api.Options{
+		ReadinessTimeout:        cfg.HTTP.ReadinessTimeout,
+		DisableStats:            !cfg.Features.Stats,
+		DisableTeamDeactivation: !cfg.Features.TeamDeactivation,
+		GitHubWebhookSecret:     cfg.Integrations.GitHub.WebhookSecret,
+		GitLabWebhookToken:      cfg.Integrations.GitLab.WebhookToken,
+		GitLabReviewers:         gitlabReviewers,
+		Logger:                  logger,
+	})
+
+	mux := http.NewServeMux()
+	handlerOpts := api.StdHTTPServerOptions{BaseRouter: mux}
+	if m != nil {
+		mux.Handle("GET /metrics", m.Handler())
+		handlerOpts.Middlewares = []api.MiddlewareFunc{m.Middleware}
+	}
+	handlerOpts.ErrorHandlerFunc = api.ParamErrorHandler(handlerOpts.Middlewares...)
+	handler := api.HandlerWithOptions(server, handlerOpts)
+
+	httpServer := &http.Server{
+		Addr:    ":" + cfg.HTTP.Port,
+		Handler: api.WithRequestID(api.WithAccessLog(api.WithRequestTimeout(api.WithActor(handler), cfg.HTTP.RequestTimeout), logger)),
+		// Заголовки читаются с отдельным лимитом, чтобы медленные клиенты не удерживали горутины
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Info("Сервер запущен", slog.String("addr", httpServer.Addr))
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	// Повторный сигнал завершает процесс сразу, не дожидаясь запросов
+	stop()
+
+	shutdownTimeout := cfg.HTTP.ShutdownTimeout
+	logger.Info("Получен сигнал остановки, ожидаем завершения запросов", slog.Duration("timeout", shutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("запросы не завершились за %s: %w", shutdownTimeout, err)
+	}
+	// Фоновая запись ревьюверов в GitLab ограничена своим таймаутом
+	server.Wait()
+	logger.Info("Сервер остановлен")
+	return nil
 }